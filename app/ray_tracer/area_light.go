@@ -0,0 +1,77 @@
+package ray_tracer
+
+import "math"
+
+// Rectangular light split into usteps x vsteps cells. Every cell is sampled separately,
+// so a point may see only a part of the light, which gives soft shadows
+type AreaLight struct {
+	corner Point3
+	// edges of a single cell
+	uvec   Vec3
+	usteps int
+	vvec   Vec3
+	vsteps int
+	// every cell is sampled in a random point instead of its center, if set.
+	// It hides the banding of the penumbrae, when there are few cells
+	jitter    bool
+	intensity Color
+}
+
+// fullUvec and fullVvec are the edges of the whole rectangle starting at the corner
+func NewAreaLight(corner Point3, fullUvec Vec3, usteps int, fullVvec Vec3, vsteps int, intensity Color) AreaLight {
+	if usteps <= 0 || vsteps <= 0 {
+		panic("Area light must have at least one step in both directions!")
+	}
+
+	return AreaLight{
+		corner:    corner,
+		uvec:      fullUvec.Div(float64(usteps)),
+		usteps:    usteps,
+		vvec:      fullVvec.Div(float64(vsteps)),
+		vsteps:    vsteps,
+		intensity: intensity,
+	}
+}
+
+func (l *AreaLight) SetJitter(jitter bool) {
+	l.jitter = jitter
+}
+
+func (l AreaLight) Intensity() Color {
+	return l.intensity
+}
+
+func (l AreaLight) Center() Point3 {
+	return l.corner.Add(l.uvec.Mul(float64(l.usteps) / 2)).Add(l.vvec.Mul(float64(l.vsteps) / 2))
+}
+
+// Point in the cell (u, v). offsetU and offsetV are in [0, 1), 0.5 is the center of the cell
+func (l AreaLight) pointOnLight(u, v int, offsetU, offsetV float64) Point3 {
+	return l.corner.Add(l.uvec.Mul(float64(u) + offsetU)).Add(l.vvec.Mul(float64(v) + offsetV))
+}
+
+func (l AreaLight) samplesAt(point Point3) []lightSample {
+	samples := make([]lightSample, 0, l.usteps*l.vsteps)
+	for v := 0; v < l.vsteps; v++ {
+		for u := 0; u < l.usteps; u++ {
+			offsetU, offsetV := 0.5, 0.5
+			if l.jitter {
+				offsetU, offsetV = jitterOffset(point, u, v, 0), jitterOffset(point, u, v, 1)
+			}
+			samples = append(samples, newLightSample(point, l.pointOnLight(u, v, offsetU, offsetV), l.intensity))
+		}
+	}
+	return samples
+}
+
+// Pseudo random number in [0, 1) derived from the arguments. The same point always
+// gets the same jitter, so images are reproducible and rendering in parallel
+// doesn't need any shared random generator
+func jitterOffset(point Point3, u, v, axis int) float64 {
+	h := uint64(u)<<32 | uint64(v)<<2 | uint64(axis)
+	for _, c := range []float64{point.x, point.y, point.z} {
+		h = splitMix64(h ^ math.Float64bits(c))
+	}
+	// 53 bits is the precision of float64
+	return float64(splitMix64(h)>>11) / (1 << 53)
+}
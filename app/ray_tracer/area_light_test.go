@@ -0,0 +1,141 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatingAnAreaLight(t *testing.T) {
+	l := NewAreaLight(NewPoint3(0, 0, 0), NewVec3(2, 0, 0), 4, NewVec3(0, 0, 1), 2, WHITE)
+
+	require.Equal(t, NewPoint3(0, 0, 0), l.corner)
+	require.Equal(t, NewVec3(0.5, 0, 0), l.uvec)
+	require.Equal(t, 4, l.usteps)
+	require.Equal(t, NewVec3(0, 0, 0.5), l.vvec)
+	require.Equal(t, 2, l.vsteps)
+	require.Equal(t, WHITE, l.Intensity())
+	require.Len(t, l.samplesAt(NewPoint3(0, 10, 0)), 8)
+	require.True(t, l.Center().Equal(NewPoint3(1, 0, 0.5)))
+	require.Panics(t, func() { NewAreaLight(NewPoint3(0, 0, 0), NewVec3(2, 0, 0), 0, NewVec3(0, 0, 1), 2, WHITE) })
+}
+
+func TestFindingCenterOfCellsOfAreaLight(t *testing.T) {
+	l := NewAreaLight(NewPoint3(0, 0, 0), NewVec3(2, 0, 0), 4, NewVec3(0, 0, 1), 2, WHITE)
+
+	for _, tc := range []struct {
+		u, v   int
+		expect Point3
+	}{
+		{0, 0, NewPoint3(0.25, 0, 0.25)},
+		{1, 0, NewPoint3(0.75, 0, 0.25)},
+		{0, 1, NewPoint3(0.25, 0, 0.75)},
+		{2, 0, NewPoint3(1.25, 0, 0.25)},
+		{3, 1, NewPoint3(1.75, 0, 0.75)},
+	} {
+		require.True(t, l.pointOnLight(tc.u, tc.v, 0.5, 0.5).Equal(tc.expect), "cell (%d, %d)", tc.u, tc.v)
+	}
+}
+
+func TestAreaLightIntensityIsFractionOfVisibleCells(t *testing.T) {
+	w := NewDefaultWorld()
+	l := NewAreaLight(NewPoint3(-0.5, -0.5, -5), NewVec3(1, 0, 0), 2, NewVec3(0, 1, 0), 2, WHITE)
+
+	for _, tc := range []struct {
+		point  Point3
+		expect float64
+	}{
+		{NewPoint3(0, 0, 2), 0},
+		{NewPoint3(1, -1, 2), 0.25},
+		{NewPoint3(1.5, 0, 2), 0.5},
+		{NewPoint3(1.25, 1.25, 3), 0.75},
+		{NewPoint3(0, 0, -2), 1},
+	} {
+		require.InDelta(t, tc.expect, LightIntensityAt(w, l, tc.point), EPSILON, tc.point)
+	}
+}
+
+func TestAreaLightIsShadowedOnlyIfAllCellsAreBlocked(t *testing.T) {
+	w := NewDefaultWorld()
+	l := NewAreaLight(NewPoint3(-0.5, -0.5, -5), NewVec3(1, 0, 0), 2, NewVec3(0, 1, 0), 2, WHITE)
+
+	require.True(t, IsShadowed(w, l, NewPoint3(0, 0, 2)))
+	require.False(t, IsShadowed(w, l, NewPoint3(1.5, 0, 2)))
+}
+
+func TestLightingAveragesSamplesOfAreaLight(t *testing.T) {
+	l := NewAreaLight(NewPoint3(-0.5, -0.5, -5), NewVec3(1, 0, 0), 2, NewVec3(0, 1, 0), 2, WHITE)
+	s := NewDefaultSphere()
+	s.material.ambient = 0.1
+	s.material.diffuse = 0.9
+	s.material.specular = 0
+	eye := NewPoint3(0, 0, -5)
+
+	for _, tc := range []struct {
+		point  Point3
+		expect Color
+	}{
+		{NewPoint3(0, 0, -1), NewColor(0.9965, 0.9965, 0.9965)},
+		{NewPoint3(0, 0.7071, -0.7071), NewColor(0.62318, 0.62318, 0.62318)},
+	} {
+		eyev := eye.Sub(tc.point).Normalize()
+		normalv := tc.point.Sub(NewPoint3(0, 0, 0))
+
+		res := CalcLighting(s.material, &s, l, tc.point, eyev, normalv, false)
+
+		require.InDelta(t, tc.expect.r, res.r, 1e-4, tc.point)
+		require.InDelta(t, tc.expect.g, res.g, 1e-4, tc.point)
+		require.InDelta(t, tc.expect.b, res.b, 1e-4, tc.point)
+	}
+}
+
+func TestJitteredSamplesStayInsideTheirCells(t *testing.T) {
+	l := NewAreaLight(NewPoint3(0, 0, 0), NewVec3(2, 0, 0), 4, NewVec3(0, 0, 1), 2, WHITE)
+	l.SetJitter(true)
+	point := NewPoint3(1, -10, 0.5)
+
+	samples := l.samplesAt(point)
+
+	centers := 0
+	for i, sample := range samples {
+		u, v := i%l.usteps, i/l.usteps
+		onLight := point.Add(sample.direction.Mul(sample.distance))
+		require.InDelta(t, l.pointOnLight(u, v, 0.5, 0.5).x, onLight.x, 0.25, "cell (%d, %d)", u, v)
+		require.InDelta(t, l.pointOnLight(u, v, 0.5, 0.5).z, onLight.z, 0.25, "cell (%d, %d)", u, v)
+		if onLight.Equal(l.pointOnLight(u, v, 0.5, 0.5)) {
+			centers++
+		}
+	}
+	require.Less(t, centers, len(samples))
+	// the same point is always jittered the same way
+	require.Equal(t, samples, l.samplesAt(point))
+}
+
+func TestJitterOffsetIsInUnitInterval(t *testing.T) {
+	sum := 0.
+	for i := 0; i < 1000; i++ {
+		offset := jitterOffset(NewPoint3(float64(i)*0.01, 1, 2), i%7, i%3, i%2)
+		require.GreaterOrEqual(t, offset, 0.)
+		require.Less(t, offset, 1.)
+		sum += offset
+	}
+	require.InDelta(t, 0.5, sum/1000, 0.05)
+}
+
+func TestShadeHitWithAreaLightGivesSoftShadows(t *testing.T) {
+	w := NewWorld()
+	floor := NewDefaultPlane()
+	w.Add("floor", &floor)
+	blocker := NewDefaultSphere()
+	blocker.SetTransform(NewTranslationMatrix(0, 2, 0))
+	w.Add("blocker", &blocker)
+	w.AddLight("area", NewAreaLight(NewPoint3(-2, 5, -2), NewVec3(4, 0, 0), 8, NewVec3(0, 0, 4), 8, WHITE))
+	r := NewRay(NewPoint3(0, 1, -3), NewVec3(0, -1, 0))
+
+	umbra := w.ColorAtIntersection(NewRay(NewPoint3(0, 0.5, 0), NewVec3(0, -1, 0)), 0)
+	penumbra := w.ColorAtIntersection(NewRay(NewPoint3(1.5, 0.5, 0), NewVec3(0, -1, 0)), 0)
+	lit := w.ColorAtIntersection(r, 0)
+
+	require.Less(t, umbra.r, penumbra.r)
+	require.Less(t, penumbra.r, lit.r)
+}
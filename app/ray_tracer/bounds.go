@@ -0,0 +1,132 @@
+package ray_tracer
+
+import "math"
+
+// Axis-aligned bounding box. Empty box has min == +Inf and max == -Inf,
+// so adding any point to it makes it valid
+type BoundingBox struct {
+	min Point3
+	max Point3
+}
+
+func NewBoundingBox(min, max Point3) BoundingBox {
+	return BoundingBox{min: min, max: max}
+}
+
+func NewEmptyBoundingBox() BoundingBox {
+	inf := math.Inf(1)
+	return BoundingBox{min: NewPoint3(inf, inf, inf), max: NewPoint3(-inf, -inf, -inf)}
+}
+
+func NewInfiniteBoundingBox() BoundingBox {
+	inf := math.Inf(1)
+	return BoundingBox{min: NewPoint3(-inf, -inf, -inf), max: NewPoint3(inf, inf, inf)}
+}
+
+func (b BoundingBox) Min() Point3 {
+	return b.min
+}
+
+func (b BoundingBox) Max() Point3 {
+	return b.max
+}
+
+func (b BoundingBox) IsEmpty() bool {
+	return b.min.x > b.max.x || b.min.y > b.max.y || b.min.z > b.max.z
+}
+
+func (b BoundingBox) IsFinite() bool {
+	for _, c := range []float64{b.min.x, b.min.y, b.min.z, b.max.x, b.max.y, b.max.z} {
+		if math.IsInf(c, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b BoundingBox) AddPoint(p Point3) BoundingBox {
+	return BoundingBox{
+		min: NewPoint3(math.Min(b.min.x, p.x), math.Min(b.min.y, p.y), math.Min(b.min.z, p.z)),
+		max: NewPoint3(math.Max(b.max.x, p.x), math.Max(b.max.y, p.y), math.Max(b.max.z, p.z)),
+	}
+}
+
+func (b BoundingBox) Merge(other BoundingBox) BoundingBox {
+	if other.IsEmpty() {
+		return b
+	}
+	return b.AddPoint(other.min).AddPoint(other.max)
+}
+
+func (b BoundingBox) ContainsPoint(p Point3) bool {
+	return b.min.x <= p.x && p.x <= b.max.x &&
+		b.min.y <= p.y && p.y <= b.max.y &&
+		b.min.z <= p.z && p.z <= b.max.z
+}
+
+func (b BoundingBox) ContainsBox(other BoundingBox) bool {
+	return b.ContainsPoint(other.min) && b.ContainsPoint(other.max)
+}
+
+func (b BoundingBox) Center() Point3 {
+	return NewPoint3((b.min.x+b.max.x)/2, (b.min.y+b.max.y)/2, (b.min.z+b.max.z)/2)
+}
+
+// Returns 0, 1 or 2 for X, Y or Z respectively
+func (b BoundingBox) LongestAxis() int {
+	dx, dy, dz := b.max.x-b.min.x, b.max.y-b.min.y, b.max.z-b.min.z
+	if dx >= dy && dx >= dz {
+		return 0
+	} else if dy >= dz {
+		return 1
+	}
+	return 2
+}
+
+// Transforms all 8 corners of the box and finds a new box containing all of them.
+// Infinite boxes stay infinite, because transforming infinities produces NaNs
+func (b BoundingBox) Transform(m *Matrix) BoundingBox {
+	if b.IsEmpty() {
+		return b
+	}
+	if !b.IsFinite() {
+		return NewInfiniteBoundingBox()
+	}
+
+	transformed := NewEmptyBoundingBox()
+	for _, x := range []float64{b.min.x, b.max.x} {
+		for _, y := range []float64{b.min.y, b.max.y} {
+			for _, z := range []float64{b.min.z, b.max.z} {
+				transformed = transformed.AddPoint(m.MulPoint(NewPoint3(x, y, z)))
+			}
+		}
+	}
+	return transformed
+}
+
+// Unlike shapes, the box is checked along the whole line of the ray (including
+// negative t), so culling by the box never changes the list of intersections
+func (b BoundingBox) IntersectsWith(r *Ray) bool {
+	if b.IsEmpty() {
+		return false
+	}
+
+	xtmin, xtmax := checkAxis(r.origin.x, r.direction.x, b.min.x, b.max.x)
+	ytmin, ytmax := checkAxis(r.origin.y, r.direction.y, b.min.y, b.max.y)
+	ztmin, ztmax := checkAxis(r.origin.z, r.direction.z, b.min.z, b.max.z)
+
+	tmin := math.Max(xtmin, math.Max(ytmin, ztmin))
+	tmax := math.Min(xtmax, math.Min(ytmax, ztmax))
+
+	return tmin <= tmax
+}
+
+// Bounds of the shape in the space of its parent (or the world space for the top level shapes).
+// Moving shapes are bounded during the whole motion
+func parentSpaceBounds(s Shape) BoundingBox {
+	if len(s.Motion()) > 0 {
+		return newMotion(s.Motion()).bounds(s.Bounds())
+	}
+	t := s.Transform()
+	return s.Bounds().Transform(&t)
+}
@@ -0,0 +1,157 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatingEmptyBoundingBox(t *testing.T) {
+	b := NewEmptyBoundingBox()
+
+	require.True(t, b.IsEmpty())
+	require.True(t, math.IsInf(b.min.x, 1))
+	require.True(t, math.IsInf(b.max.x, -1))
+}
+
+func TestAddingPointsToEmptyBoundingBox(t *testing.T) {
+	b := NewEmptyBoundingBox()
+
+	b = b.AddPoint(NewPoint3(-5, 2, 0)).AddPoint(NewPoint3(7, 0, -3))
+
+	require.True(t, b.min.Equal(NewPoint3(-5, 0, -3)))
+	require.True(t, b.max.Equal(NewPoint3(7, 2, 0)))
+}
+
+func TestShapesHaveBoundingBoxes(t *testing.T) {
+	inf := math.Inf(1)
+	sphere, cube, plane := NewDefaultSphere(), NewDefaultCube(), NewDefaultPlane()
+	cylinder, cone := NewDefaultCylinder(), NewDefaultCone()
+	cylinder.Truncate(-5, 3, true)
+	cone.Truncate(-5, 3, true)
+	tri := NewDefaultTriangle(NewPoint3(-3, 7, 2), NewPoint3(6, 2, -4), NewPoint3(2, -1, -1))
+	testCases := []struct {
+		shape    Shape
+		min, max Point3
+	}{
+		{&sphere, NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1)},
+		{&cube, NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1)},
+		{&plane, NewPoint3(-inf, 0, -inf), NewPoint3(inf, 0, inf)},
+		{&cylinder, NewPoint3(-1, -5, -1), NewPoint3(1, 3, 1)},
+		{&cone, NewPoint3(-5, -5, -5), NewPoint3(5, 3, 5)},
+		{&tri, NewPoint3(-3, -1, -4), NewPoint3(6, 7, 2)},
+	}
+
+	for _, tc := range testCases {
+		b := tc.shape.Bounds()
+
+		require.Equal(t, tc.min, b.min, tc.shape.Id())
+		require.Equal(t, tc.max, b.max, tc.shape.Id())
+	}
+}
+
+func TestMergingBoundingBoxes(t *testing.T) {
+	b1 := NewBoundingBox(NewPoint3(-5, -2, 0), NewPoint3(7, 4, 4))
+	b2 := NewBoundingBox(NewPoint3(8, -7, -2), NewPoint3(14, 2, 8))
+
+	b := b1.Merge(b2)
+
+	require.True(t, b.min.Equal(NewPoint3(-5, -7, -2)))
+	require.True(t, b.max.Equal(NewPoint3(14, 4, 8)))
+	require.Equal(t, b1, b1.Merge(NewEmptyBoundingBox()))
+}
+
+func TestCheckingIfBoxContainsPointOrBox(t *testing.T) {
+	b := NewBoundingBox(NewPoint3(5, -2, 0), NewPoint3(11, 4, 7))
+
+	require.True(t, b.ContainsPoint(NewPoint3(5, -2, 0)))
+	require.True(t, b.ContainsPoint(NewPoint3(8, 1, 3)))
+	require.False(t, b.ContainsPoint(NewPoint3(3, 0, 3)))
+	require.False(t, b.ContainsPoint(NewPoint3(8, 1, 8)))
+	require.True(t, b.ContainsBox(NewBoundingBox(NewPoint3(6, -1, 1), NewPoint3(10, 3, 6))))
+	require.False(t, b.ContainsBox(NewBoundingBox(NewPoint3(4, -3, -1), NewPoint3(10, 3, 6))))
+}
+
+func TestTransformingBoundingBox(t *testing.T) {
+	b := NewBoundingBox(NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1))
+	m := NewRotationXMatrix(math.Pi / 4).MulMat(NewRotationYMatrix(math.Pi / 4))
+
+	b2 := b.Transform(m)
+
+	require.True(t, b2.min.Equal(NewPoint3(-1.41421, -1.70710, -1.70710)))
+	require.True(t, b2.max.Equal(NewPoint3(1.41421, 1.70710, 1.70710)))
+}
+
+func TestTransformingInfiniteBoundingBoxKeepsItInfinite(t *testing.T) {
+	p := NewDefaultPlane()
+
+	b := p.Bounds().Transform(NewRotationXMatrix(math.Pi / 2))
+
+	require.False(t, b.IsFinite())
+	require.True(t, b.ContainsPoint(NewPoint3(0, 1000, 0)))
+}
+
+func TestBoundsOfShapeInParentSpace(t *testing.T) {
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(1, -3, 5).MulMat(NewScalingMatrix(0.5, 2, 4)))
+
+	b := parentSpaceBounds(&s)
+
+	require.True(t, b.min.Equal(NewPoint3(0.5, -5, 1)))
+	require.True(t, b.max.Equal(NewPoint3(1.5, -1, 9)))
+}
+
+func TestGroupAndCSGBoundsContainAllChildren(t *testing.T) {
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(2, 5, -3).MulMat(NewScalingMatrix(2, 2, 2)))
+	c := NewDefaultCylinder()
+	c.Truncate(-2, 2, false)
+	c.SetTransform(NewTranslationMatrix(-4, -1, 4).MulMat(NewScalingMatrix(0.5, 1, 0.5)))
+	g := NewDefaultGroup()
+	g.AddChild(&s)
+	g.AddChild(&c)
+
+	b := g.Bounds()
+
+	require.True(t, b.min.Equal(NewPoint3(-4.5, -3, -5)))
+	require.True(t, b.max.Equal(NewPoint3(4, 7, 4.5)))
+
+	left, right := NewDefaultSphere(), NewDefaultSphere()
+	right.SetTransform(NewTranslationMatrix(2, 3, 4))
+	csg := NewCSG("csg_id", CSG_DIFFERENCE, &left, &right)
+	b = csg.Bounds()
+	require.True(t, b.min.Equal(NewPoint3(-1, -1, -1)))
+	require.True(t, b.max.Equal(NewPoint3(3, 4, 5)))
+}
+
+func TestIntersectingRayWithBoundingBox(t *testing.T) {
+	b := NewBoundingBox(NewPoint3(5, -2, 0), NewPoint3(11, 4, 7))
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+		result    bool
+	}{
+		{NewPoint3(15, 1, 2), NewVec3(-1, 0, 0), true},
+		{NewPoint3(-5, -1, 4), NewVec3(1, 0, 0), true},
+		{NewPoint3(7, 6, 5), NewVec3(0, -1, 0), true},
+		{NewPoint3(9, -5, 6), NewVec3(0, 1, 0), true},
+		{NewPoint3(8, 2, 12), NewVec3(0, 0, -1), true},
+		{NewPoint3(6, 0, -5), NewVec3(0, 0, 1), true},
+		{NewPoint3(8, 1, 3.5), NewVec3(0, 0, 1), true},
+		{NewPoint3(9, -1, -8), NewVec3(2, 4, 6), false},
+		{NewPoint3(8, 3, -4), NewVec3(6, 2, 4), false},
+		{NewPoint3(9, -1, -2), NewVec3(4, 6, 2), false},
+		{NewPoint3(4, 0, 9), NewVec3(0, 0, -1), false},
+		{NewPoint3(8, 6, -1), NewVec3(0, -1, 0), false},
+		{NewPoint3(12, 5, 4), NewVec3(-1, 0, 0), false},
+		// box behind the ray still counts, because the whole line is checked
+		{NewPoint3(15, 1, 2), NewVec3(1, 0, 0), true},
+	}
+
+	for i, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		require.Equal(t, tc.result, b.IntersectsWith(&r), "case %d", i+1)
+	}
+}
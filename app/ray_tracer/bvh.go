@@ -0,0 +1,154 @@
+package ray_tracer
+
+import "sort"
+
+// Nodes with this many shapes or less aren't split any further
+const BVH_MAX_LEAF_SHAPES = 4
+
+// Bounding volume hierarchy node. Only leaves hold shapes, inner nodes
+// always have both children
+type bvhNode struct {
+	bounds BoundingBox
+	shapes []Shape
+	left   *bvhNode
+	right  *bvhNode
+}
+
+type BVHStats struct {
+	// Total number of nodes, including leaves
+	Nodes  int
+	Leaves int
+	// Shapes stored in the leaves
+	Shapes int
+	// Shapes with infinite bounds (e.g. planes), which are tested with every ray
+	Unbounded int
+	MaxDepth  int
+}
+
+func (s BVHStats) add(other BVHStats) BVHStats {
+	s.Nodes += other.Nodes
+	s.Leaves += other.Leaves
+	s.Shapes += other.Shapes
+	s.Unbounded += other.Unbounded
+	if other.MaxDepth > s.MaxDepth {
+		s.MaxDepth = other.MaxDepth
+	}
+	return s
+}
+
+// Bounding volume hierarchy over a list of shapes. Rays skip all the shapes
+// in the nodes whose bounding boxes they miss
+type bvh struct {
+	root      *bvhNode
+	unbounded []Shape
+	stats     BVHStats
+}
+
+type bvhItem struct {
+	shape  Shape
+	bounds BoundingBox
+	center Point3
+}
+
+func axisValue(t Point3, axis int) float64 {
+	switch axis {
+	case 0:
+		return t.x
+	case 1:
+		return t.y
+	default:
+		return t.z
+	}
+}
+
+// Splits the shapes in half by the median of their centers along the longest axis
+func buildBVHNode(items []bvhItem, depth int, stats *BVHStats) *bvhNode {
+	stats.Nodes++
+	if depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+
+	node := &bvhNode{bounds: NewEmptyBoundingBox()}
+	centers := NewEmptyBoundingBox()
+	for _, item := range items {
+		node.bounds = node.bounds.Merge(item.bounds)
+		centers = centers.AddPoint(item.center)
+	}
+
+	if len(items) <= BVH_MAX_LEAF_SHAPES {
+		stats.Leaves++
+		stats.Shapes += len(items)
+		for _, item := range items {
+			node.shapes = append(node.shapes, item.shape)
+		}
+		return node
+	}
+
+	axis := centers.LongestAxis()
+	sort.SliceStable(items, func(i, j int) bool {
+		return axisValue(items[i].center, axis) < axisValue(items[j].center, axis)
+	})
+
+	middle := len(items) / 2
+	node.left = buildBVHNode(items[:middle], depth+1, stats)
+	node.right = buildBVHNode(items[middle:], depth+1, stats)
+	return node
+}
+
+func buildBVH(shapes []Shape) *bvh {
+	b := &bvh{}
+	items := []bvhItem{}
+	for _, s := range shapes {
+		bounds := parentSpaceBounds(s)
+		if !bounds.IsFinite() {
+			b.unbounded = append(b.unbounded, s)
+			continue
+		}
+		items = append(items, bvhItem{shape: s, bounds: bounds, center: bounds.Center()})
+	}
+
+	b.stats.Unbounded = len(b.unbounded)
+	if len(items) > 0 {
+		b.root = buildBVHNode(items, 1, &b.stats)
+	}
+	return b
+}
+
+func (n *bvhNode) intersect(r *Ray, xs []Intersection) []Intersection {
+	if !n.bounds.IntersectsWith(r) {
+		return xs
+	}
+
+	for _, s := range n.shapes {
+		xs = append(xs, IntersectWith(s, r)...)
+	}
+	if n.left != nil {
+		xs = n.left.intersect(r, xs)
+		xs = n.right.intersect(r, xs)
+	}
+	return xs
+}
+
+// Returned intersections are not sorted
+func (b *bvh) intersect(r *Ray) []Intersection {
+	xs := []Intersection{}
+	for _, s := range b.unbounded {
+		xs = append(xs, IntersectWith(s, r)...)
+	}
+	if b.root != nil {
+		xs = b.root.intersect(r, xs)
+	}
+	return xs
+}
+
+// Builds hierarchies inside of the groups, which may be nested in the shape
+func buildNestedBVHs(s Shape) BVHStats {
+	switch s := s.(type) {
+	case *Group:
+		return s.BuildBVH()
+	case *CSG:
+		return buildNestedBVHs(s.left).add(buildNestedBVHs(s.right))
+	default:
+		return BVHStats{}
+	}
+}
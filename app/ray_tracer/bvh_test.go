@@ -0,0 +1,278 @@
+package ray_tracer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// n*n*n small spheres in a grid from (0,0,0) to (n-1,n-1,n-1)
+func createWorldWithSphereGrid(n int) *World {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(-10, 10, -10), WHITE))
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			for z := 0; z < n; z++ {
+				s := NewSphere(fmt.Sprintf("s_%d_%d_%d", x, y, z), NewDefaultMaterial())
+				s.SetTransform(NewTranslationMatrix(float64(x), float64(y), float64(z)).MulMat(NewScalingMatrix(0.3, 0.3, 0.3)))
+				w.Add(s.id, &s)
+			}
+		}
+	}
+	return w
+}
+
+func requireSameIntersections(t *testing.T, expect, res []Intersection) {
+	require.Len(t, res, len(expect))
+	for i := range expect {
+		require.InDelta(t, expect[i].time, res[i].time, EPSILON)
+		require.Equal(t, expect[i].object.Id(), res[i].object.Id())
+	}
+}
+
+func TestBuildingBVHOfWorld(t *testing.T) {
+	w := createWorldWithSphereGrid(4)
+	floor := NewDefaultPlane()
+	w.Add("floor", &floor)
+
+	stats := w.BuildBVH()
+
+	require.Equal(t, 64, stats.Shapes)
+	require.Equal(t, 1, stats.Unbounded)
+	require.Equal(t, 16, stats.Leaves)
+	require.Equal(t, 31, stats.Nodes)
+	require.Equal(t, 5, stats.MaxDepth)
+	worldStats, ok := w.BVHStats()
+	require.True(t, ok)
+	require.Equal(t, stats, worldStats)
+}
+
+func TestWorldWithoutBVHHasNoStats(t *testing.T) {
+	w := NewDefaultWorld()
+
+	_, ok := w.BVHStats()
+
+	require.False(t, ok)
+}
+
+func TestIntersectionsWithBVHAreTheSameAsWithoutIt(t *testing.T) {
+	w := createWorldWithSphereGrid(5)
+	rays := []Ray{
+		NewRay(NewPoint3(-5, 0, 0), NewVec3(1, 0, 0)),
+		NewRay(NewPoint3(2, 2, -5), NewVec3(0, 0, 1)),
+		NewRay(NewPoint3(-3, -2, -4), NewVec3(1, 0.9, 1.1).Normalize()),
+		NewRay(NewPoint3(2, 2, 2), NewVec3(0.3, -1, 0.2).Normalize()),
+		NewRay(NewPoint3(10, 10, 10), NewVec3(0, 1, 0)),
+	}
+	expect := [][]Intersection{}
+	for i := range rays {
+		expect = append(expect, w.IntersectWith(&rays[i]))
+	}
+
+	w.BuildBVH()
+
+	for i := range rays {
+		requireSameIntersections(t, expect[i], w.IntersectWith(&rays[i]))
+	}
+}
+
+func TestOutdatedWorldBVHIsIgnored(t *testing.T) {
+	w := createWorldWithSphereGrid(2)
+	w.BuildBVH()
+	s := NewSphere("new_sphere", NewDefaultMaterial())
+	s.SetTransform(NewTranslationMatrix(0, 0, -5))
+	w.Add("new_sphere", &s)
+	r := NewRay(NewPoint3(0, 0, -10), NewVec3(0, 0, 1))
+
+	xs := w.IntersectWith(&r)
+
+	_, ok := w.BVHStats()
+	require.False(t, ok)
+	require.Len(t, xs, 6)
+	require.Equal(t, &s, xs[0].object)
+
+	stats := w.BuildBVH()
+
+	require.Equal(t, 9, stats.Shapes)
+	requireSameIntersections(t, xs, w.IntersectWith(&r))
+}
+
+func TestReplacingShapeDropsWorldBVH(t *testing.T) {
+	w := NewDefaultWorld()
+	w.BuildBVH()
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(0, 5, 0))
+	w.Add("s1", &s)
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+
+	xs := w.IntersectWith(&r)
+
+	_, ok := w.BVHStats()
+	require.False(t, ok)
+	require.Len(t, xs, 2)
+	require.InDelta(t, 4.5, xs[0].time, EPSILON)
+}
+
+func TestRemovedShapeIsNotHitEvenIfLightIsAdded(t *testing.T) {
+	w := NewDefaultWorld()
+	w.BuildBVH()
+	w.Remove("s2")
+	w.AddLight("fill", NewPointLight(NewPoint3(10, 10, -10), WHITE))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+
+	xs := w.IntersectWith(&r)
+
+	require.Len(t, xs, 2)
+	require.Equal(t, 3, w.Len())
+}
+
+func TestChangingLightKeepsWorldBVH(t *testing.T) {
+	w := NewDefaultWorld()
+	stats := w.BuildBVH()
+
+	w.SetLight(NewPointLight(NewPoint3(10, 10, -10), WHITE))
+
+	worldStats, ok := w.BVHStats()
+	require.True(t, ok)
+	require.Equal(t, stats, worldStats)
+}
+
+func TestMovingShapeDropsWorldBVH(t *testing.T) {
+	w := createWorldWithSphereGrid(2)
+	w.BuildBVH()
+	s := w.Sphere("s_0_0_0")
+	s.SetTransform(NewTranslationMatrix(0, 0, -5))
+	r := NewRay(NewPoint3(0, 0, -10), NewVec3(0, 0, 1))
+
+	xs := w.IntersectWith(&r)
+
+	_, ok := w.BVHStats()
+	require.False(t, ok)
+	require.Equal(t, s, xs[0].object)
+}
+
+func TestRemovedShapeDoesNotDropWorldBVH(t *testing.T) {
+	w := NewDefaultWorld()
+	s := w.Sphere("s2")
+	w.Remove("s2")
+	w.BuildBVH()
+
+	s.SetTransform(NewTranslationMatrix(0, 1, 0))
+
+	_, ok := w.BVHStats()
+	require.True(t, ok)
+}
+
+func TestBuildingBVHOfGroupSplitsItsChildren(t *testing.T) {
+	g := NewDefaultGroup()
+	for i := 0; i < 10; i++ {
+		s := NewSphere(fmt.Sprintf("s%d", i), NewDefaultMaterial())
+		s.SetTransform(NewTranslationMatrix(float64(3*i), 0, 0))
+		g.AddChild(&s)
+	}
+	r := NewRay(NewPoint3(-5, 0, 0), NewVec3(1, 0, 0))
+	expect := g.IntersectWith(&r)
+
+	stats := g.BuildBVH()
+
+	require.Equal(t, 10, stats.Shapes)
+	require.Equal(t, 4, stats.Leaves)
+	require.Equal(t, 7, stats.Nodes)
+	requireSameIntersections(t, expect, g.IntersectWith(&r))
+}
+
+func TestAddingChildDropsGroupBVH(t *testing.T) {
+	g := NewDefaultGroup()
+	s1 := NewDefaultSphere()
+	g.AddChild(&s1)
+	g.BuildBVH()
+
+	s2 := NewDefaultSphere()
+	g.AddChild(&s2)
+
+	require.Nil(t, g.bvh)
+}
+
+func TestAddingChildToNestedGroupDropsAllBVHsAboveIt(t *testing.T) {
+	inner := NewGroup("inner")
+	outer := NewGroup("outer")
+	outer.AddChild(inner)
+	w := NewWorld()
+	w.Add("outer", outer)
+	w.BuildBVH()
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(0, 0, 5))
+
+	inner.AddChild(&s)
+
+	_, ok := w.BVHStats()
+	require.False(t, ok)
+	require.Nil(t, outer.bvh)
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+	require.Len(t, w.IntersectWith(&r), 2)
+}
+
+func TestWorldBVHIncludesNestedGroups(t *testing.T) {
+	w := NewWorld()
+	inner := NewGroup("inner")
+	for i := 0; i < 8; i++ {
+		tri := NewDefaultTriangle(NewPoint3(float64(i), 1, 0), NewPoint3(float64(i)-1, 0, 0), NewPoint3(float64(i)+1, 0, 0))
+		inner.AddChild(&tri)
+	}
+	outer := NewGroup("outer")
+	outer.AddChild(inner)
+	outer.SetTransform(NewTranslationMatrix(0, 0, 5))
+	w.Add("mesh", outer)
+	r := NewRay(NewPoint3(3, 0.5, 0), NewVec3(0, 0, 1))
+	expect := w.IntersectWith(&r)
+
+	stats := w.BuildBVH()
+
+	require.Equal(t, 8+1+1, stats.Shapes)
+	require.NotNil(t, inner.bvh)
+	require.NotNil(t, outer.bvh)
+	requireSameIntersections(t, expect, w.IntersectWith(&r))
+}
+
+func BenchmarkWorldIntersectionWithoutBVH(b *testing.B) {
+	w := createWorldWithSphereGrid(10)
+	r := NewRay(NewPoint3(-5, 4.5, 4.5), NewVec3(1, 0, 0))
+
+	for i := 0; i < b.N; i++ {
+		w.IntersectWith(&r)
+	}
+}
+
+func BenchmarkWorldIntersectionWithBVH(b *testing.B) {
+	w := createWorldWithSphereGrid(10)
+	w.BuildBVH()
+	r := NewRay(NewPoint3(-5, 4.5, 4.5), NewVec3(1, 0, 0))
+
+	for i := 0; i < b.N; i++ {
+		w.IntersectWith(&r)
+	}
+}
+
+func TestBVHFindsMovingShapesDuringTheWholeMotion(t *testing.T) {
+	w := createWorldWithSphereGrid(3)
+	moving := NewDefaultSphere()
+	moving.SetMotion(
+		NewKeyframe(0, NewTranslationMatrix(10, 0, 0)),
+		NewKeyframe(1, NewScalingMatrix(3, 0.5, 0.5).RotateZ(math.Pi/2).Translate(20, 0, 0)),
+	)
+	w.Add("moving", &moving)
+	w.BuildBVH()
+
+	for _, time := range []float64{0, 0.3, 0.5, 0.8, 1} {
+		transform := moving.motion.transformAt(time)
+		center := transform.MulPoint(NewPoint3(0, 0, 0))
+		r := NewRayAtTime(NewPoint3(center.x, center.y, -10), NewVec3(0, 0, 1), time)
+
+		xs := w.IntersectWith(&r)
+
+		require.Len(t, xs, 2, "time %v", time)
+		require.Equal(t, moving.Id(), xs[0].object.Id())
+	}
+}
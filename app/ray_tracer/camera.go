@@ -1,6 +1,13 @@
 package ray_tracer
 
-import "math"
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Size of the square tiles the image is split into for rendering in parallel
+const RENDER_TILE_SIZE = 16
 
 // Primary responsibility of the Camera is to map 3D scene onto a 2D canvas
 type Camera struct {
@@ -8,9 +15,32 @@ type Camera struct {
 	vSize       int
 	fieldOfView float64
 	transform   Matrix
-	halfWidth   float64
-	halfHeight  float64
-	pixelSize   float64
+	// every ray is transformed with the inverse, so it's calculated once in SetTransform
+	inverse    Mat4
+	halfWidth  float64
+	halfHeight float64
+	pixelSize  float64
+	// number of goroutines rendering the image, GOMAXPROCS if <= 0
+	workers int
+	// how many times rays are reflected before giving up
+	reflectionDepth int
+	// anti-aliasing: every pixel is a weighted average of this many rays
+	samplesPerPixel int
+	sampling        PixelSampling
+	filter          PixelFilter
+	// the same seed gives the same image
+	seed int64
+	// thin lens: rays start on the lens disk of aperture diameter and converge on the
+	// plane at focalDistance in front of the camera. Zero aperture is a pinhole camera
+	aperture      float64
+	focalDistance float64
+	projection    Projection
+	// width of the view in world units for the orthographic projection
+	orthographicWidth float64
+	// rays are cast at random moments between these times, so moving shapes are blurred
+	shutterOpen  float64
+	shutterClose float64
+	integrator   Integrator
 }
 
 func calcCameraParameters(hsize, vsize int, fieldOfView float64) (halfWidth, halfHeight, pixelSize float64) {
@@ -32,43 +62,360 @@ func calcCameraParameters(hsize, vsize int, fieldOfView float64) (halfWidth, hal
 func NewCamera(hsize, vsize int, fieldOfView float64) Camera {
 	halfWidth, halfHeight, pixelSize := calcCameraParameters(hsize, vsize, fieldOfView)
 	return Camera{
-		hSize:       hsize,
-		vSize:       vsize,
-		fieldOfView: fieldOfView,
-		transform:   *NewIdentityMatrix(4),
-		halfWidth:   halfWidth,
-		halfHeight:  halfHeight,
-		pixelSize:   pixelSize,
+		hSize:           hsize,
+		vSize:           vsize,
+		fieldOfView:     fieldOfView,
+		transform:       *NewIdentityMatrix(4),
+		inverse:         NewIdentityMat4(),
+		halfWidth:       halfWidth,
+		halfHeight:      halfHeight,
+		pixelSize:       pixelSize,
+		reflectionDepth: MAX_REFLECTION_DEPTH,
+		samplesPerPixel: 1,
+		sampling:        SAMPLING_REGULAR,
+		filter:          FILTER_BOX,
+		focalDistance:   1,
+		projection:      PROJECTION_PERSPECTIVE,
+		integrator:      INTEGRATOR_WHITTED,
+		// the same as the width of the canvas
+		orthographicWidth: halfWidth * 2,
 	}
 }
 
+// Returns a copy, so changing it doesn't affect the cached inverse
+func (c *Camera) Transform() Matrix {
+	return *c.transform.Copy()
+}
+
+// The matrix is copied, so changing it afterwards doesn't affect the camera
+func (c *Camera) SetTransform(m *Matrix) {
+	c.transform = *m.Copy()
+	transform := m.ToMat4()
+	c.inverse = transform.Inverse()
+}
+
+// Casts the ray through the center of the pixel
 func (c *Camera) CastRayIntoPixel(px, py int) Ray {
-	xOffset := (float64(px) + 0.5) * c.pixelSize
-	yOffset := (float64(py) + 0.5) * c.pixelSize
+	return c.CastRayThroughPixel(px, py, 0.5, 0.5)
+}
+
+// Casts the ray through the point of the pixel. Offsets are in pixels from its top left
+// corner, they may go outside of [0, 1) for filters wider than a pixel
+func (c *Camera) CastRayThroughPixel(px, py int, offsetX, offsetY float64) Ray {
+	return c.CastRayThroughLens(px, py, offsetX, offsetY, 0.5, 0.5)
+}
+
+// Like CastRayThroughPixel, but the ray starts at the point (lensU, lensV) of the lens.
+// Both are in [0, 1], (0.5, 0.5) is the center of the lens. Only the perspective
+// projection has a lens, the others are always sharp
+func (c *Camera) CastRayThroughLens(px, py int, offsetX, offsetY, lensU, lensV float64) Ray {
+	// camera space, the zero origin is the pinhole
+	var origin, target Point3
+	switch c.projection {
+	case PROJECTION_ORTHOGRAPHIC:
+		scale := c.orthographicWidth / (c.halfWidth * 2)
+		x, y := c.canvasPoint(px, py, offsetX, offsetY)
+		origin = NewPoint3(x*scale, y*scale, 0)
+		target = NewPoint3(x*scale, y*scale, -1)
+
+	case PROJECTION_FISHEYE:
+		x, y := c.fisheyePoint(px, py, offsetX, offsetY)
+		target = origin.Add(fisheyeDirection(x, y, c.fieldOfView))
+
+	case PROJECTION_EQUIRECTANGULAR:
+		u := (float64(px) + offsetX) / float64(c.hSize)
+		v := (float64(py) + offsetY) / float64(c.vSize)
+		target = origin.Add(equirectangularDirection(u, v))
+
+	default:
+		x, y := c.canvasPoint(px, py, offsetX, offsetY)
+		// remember that canvas is at z=-1, so the point in focus is the pixel
+		// moved along the ray to the focal plane
+		target = NewPoint3(x*c.focalDistance, y*c.focalDistance, -c.focalDistance)
+
+		lensX, lensY := concentricDiskSample(lensU, lensV)
+		lensRadius := c.aperture / 2
+		origin = NewPoint3(lensX*lensRadius, lensY*lensRadius, 0)
+	}
+
+	// from the camera space to the world space
+	pixel := c.inverse.MulPoint(target)
+	worldOrigin := c.inverse.MulPoint(origin)
+	direction := pixel.Sub(worldOrigin).Normalize()
+
+	return NewRay(worldOrigin, direction)
+}
+
+// Point of the pixel in [-1, 1] along the larger side of the canvas. fieldOfView may be
+// bigger than pi here, so the canvas doesn't depend on it
+func (c *Camera) fisheyePoint(px, py int, offsetX, offsetY float64) (x, y float64) {
+	larger := math.Max(float64(c.hSize), float64(c.vSize)) / 2
+	x = (float64(c.hSize)/2 - float64(px) - offsetX) / larger
+	y = (float64(c.vSize)/2 - float64(py) - offsetY) / larger
+	return x, y
+}
+
+// Fisheye image is a circle inscribed into the larger side of the canvas, the corners
+// outside of it see nothing. The other projections cover the whole canvas
+func (c *Camera) sees(px, py int, offsetX, offsetY float64) bool {
+	if c.projection != PROJECTION_FISHEYE {
+		return true
+	}
+	x, y := c.fisheyePoint(px, py, offsetX, offsetY)
+	return x*x+y*y <= 1
+}
+
+// The untransformed coordinates of the point of the pixel on the canvas at z=-1
+func (c *Camera) canvasPoint(px, py int, offsetX, offsetY float64) (x, y float64) {
+	xOffset := (float64(px) + offsetX) * c.pixelSize
+	yOffset := (float64(py) + offsetY) * c.pixelSize
+	return c.halfWidth - xOffset, c.halfHeight - yOffset
+}
+
+// Number of goroutines used by Render. Values <= 0 mean GOMAXPROCS
+func (c *Camera) SetWorkers(workers int) {
+	c.workers = workers
+}
+
+func (c *Camera) Workers() int {
+	if c.workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return c.workers
+}
+
+// Number of reflections followed for every ray. 0 turns reflections off.
+// For path tracing it's the maximum number of bounces
+func (c *Camera) SetReflectionDepth(depth int) {
+	c.reflectionDepth = depth
+}
+
+func (c *Camera) ReflectionDepth() int {
+	return c.reflectionDepth
+}
+
+// Number of rays cast into every pixel. With 1 sample (the default) the ray goes
+// through the center of the pixel and the sampling and filter are not used
+func (c *Camera) SetSamplesPerPixel(samples int) {
+	if samples <= 0 {
+		panic("Camera must cast at least one ray per pixel!")
+	}
+	c.samplesPerPixel = samples
+}
 
-	// the untransformed coordinates of the pixel in world space.
-	worldX := c.halfWidth - xOffset
-	worldY := c.halfHeight - yOffset
+func (c *Camera) SamplesPerPixel() int {
+	return c.samplesPerPixel
+}
 
-	// remember that canvas is at z=-1
-	worldPixel := NewPoint(worldX, worldY, -1)
+func (c *Camera) SetSampling(sampling PixelSampling) {
+	c.sampling = sampling
+}
 
-	// pixel in Camera space (?)
-	pixel := c.transform.Inverse().MulTuple(worldPixel)
-	origin := c.transform.Inverse().MulTuple(NewPoint(0, 0, 0))
-	direction := pixel.Sub(origin).Normalize()
+func (c *Camera) Sampling() PixelSampling {
+	return c.sampling
+}
+
+func (c *Camera) SetFilter(filter PixelFilter) {
+	c.filter = filter
+}
 
-	return NewRay(origin, direction)
+func (c *Camera) Filter() PixelFilter {
+	return c.filter
 }
 
-func (c *Camera) Render(w World) Canvas {
+// Seed of the random numbers used by jittered and low discrepancy sampling
+func (c *Camera) SetSeed(seed int64) {
+	c.seed = seed
+}
+
+func (c *Camera) Seed() int64 {
+	return c.seed
+}
+
+// Diameter of the lens. The bigger it is, the blurrier everything out of the focal
+// plane gets. 0 (the default) makes everything sharp
+func (c *Camera) SetAperture(aperture float64) {
+	if aperture < 0 {
+		panic("Aperture can't be negative!")
+	}
+	c.aperture = aperture
+}
+
+func (c *Camera) Aperture() float64 {
+	return c.aperture
+}
+
+// Distance from the camera to the plane, which is in focus
+func (c *Camera) SetFocalDistance(distance float64) {
+	if distance <= 0 {
+		panic("Focal distance must be positive!")
+	}
+	c.focalDistance = distance
+}
+
+func (c *Camera) FocalDistance() float64 {
+	return c.focalDistance
+}
+
+func (c *Camera) SetProjection(projection Projection) {
+	c.projection = projection
+}
+
+func (c *Camera) Projection() Projection {
+	return c.projection
+}
+
+// Width of the view in world units for the orthographic projection, the height
+// follows from the aspect ratio of the canvas
+func (c *Camera) SetOrthographicWidth(width float64) {
+	if width <= 0 {
+		panic("Orthographic width must be positive!")
+	}
+	c.orthographicWidth = width
+}
+
+func (c *Camera) OrthographicWidth() float64 {
+	return c.orthographicWidth
+}
+
+// Time interval the image is exposed during. Shapes moving in the meantime
+// (see Shape.SetMotion) are blurred. Both are 0 by default, so everything is still
+func (c *Camera) SetShutter(open, close float64) {
+	if close < open {
+		panic("Shutter can't close before it opens!")
+	}
+	c.shutterOpen, c.shutterClose = open, close
+}
+
+func (c *Camera) Shutter() (open, close float64) {
+	return c.shutterOpen, c.shutterClose
+}
+
+// How the colors are calculated, see Integrator. Both Render and RenderSerial use it
+func (c *Camera) SetIntegrator(integrator Integrator) {
+	c.integrator = integrator
+}
+
+func (c *Camera) Integrator() Integrator {
+	return c.integrator
+}
+
+func (c *Camera) colorAlongRay(w *World, r Ray, rng *pixelRandom) Color {
+	if c.integrator == INTEGRATOR_PATH_TRACING {
+		return w.pathTrace(r, c.reflectionDepth, rng)
+	}
+	return w.ColorAtIntersection(r, c.reflectionDepth)
+}
+
+func (c *Camera) colorAtPixel(w *World, px, py int) Color {
+	// random numbers depend only on the seed and the pixel, so the order pixels
+	// are rendered in doesn't matter
+	rng := newPixelRandom(c.seed, px, py)
+	if c.samplesPerPixel <= 1 && c.aperture == 0 && c.shutterOpen == c.shutterClose {
+		return c.colorAtPixelCenter(w, px, py, &rng)
+	}
+
+	radius := c.filter.radius()
+
+	samples := pixelSamples(c.sampling, c.samplesPerPixel, &rng)
+	// lens is sampled in the different order, otherwise the position in the pixel would
+	// define the position on the lens. Regular grid on the lens gives a few sharp copies
+	// of the image instead of the blur, so it's jittered
+	lensSampling := c.sampling
+	if lensSampling == SAMPLING_REGULAR {
+		lensSampling = SAMPLING_JITTERED
+	}
+	lensSamples := pixelSamples(lensSampling, c.samplesPerPixel, &rng)
+	shuffle(lensSamples, &rng)
+	times := stratifiedSamples1D(c.samplesPerPixel, &rng)
+
+	sum, totalWeight := BLACK, 0.
+	for i, sample := range samples {
+		// samples are stretched over the whole filter, centered at the pixel center
+		dx, dy := (sample[0]-0.5)*2*radius, (sample[1]-0.5)*2*radius
+		weight := c.filter.weight(dx, dy)
+		if weight == 0 {
+			continue
+		}
+
+		totalWeight += weight
+		if !c.sees(px, py, 0.5+dx, 0.5+dy) {
+			continue
+		}
+
+		r := c.CastRayThroughLens(px, py, 0.5+dx, 0.5+dy, lensSamples[i][0], lensSamples[i][1])
+		r.time = c.shutterOpen + times[i]*(c.shutterClose-c.shutterOpen)
+		sum = sum.Add(c.colorAlongRay(w, r, &rng).MultScalar(weight))
+	}
+
+	// negative lobes of Mitchell filter may cancel out everything
+	if math.Abs(totalWeight) < EPSILON {
+		return c.colorAtPixelCenter(w, px, py, &rng)
+	}
+	return sum.MultScalar(1 / totalWeight)
+}
+
+func (c *Camera) colorAtPixelCenter(w *World, px, py int, rng *pixelRandom) Color {
+	if !c.sees(px, py, 0.5, 0.5) {
+		return BLACK
+	}
+	r := c.CastRayIntoPixel(px, py)
+	r.time = c.shutterOpen
+	return c.colorAlongRay(w, r, rng)
+}
+
+// Renders the image pixel by pixel on the current goroutine
+func (c *Camera) RenderSerial(w *World) Canvas {
+	canvas := NewCanvas(c.hSize, c.vSize)
+	c.renderTile(w, &canvas, renderTile{0, 0, c.hSize, c.vSize})
+	return canvas
+}
+
+// Part of the image: from (x0, y0) inclusive to (x1, y1) exclusive
+type renderTile struct {
+	x0, y0 int
+	x1, y1 int
+}
+
+func (c *Camera) renderTile(w *World, canvas *Canvas, tile renderTile) {
+	for y := tile.y0; y < tile.y1; y++ {
+		for x := tile.x0; x < tile.x1; x++ {
+			canvas.WritePixel(x, y, c.colorAtPixel(w, x, y))
+		}
+	}
+}
+
+// Splits the image into tiles and renders them in parallel. Every pixel is calculated
+// exactly like in RenderSerial, so the images are identical.
+// The world is only read during rendering, so it must not be changed until Render returns
+func (c *Camera) Render(w *World) Canvas {
 	canvas := NewCanvas(c.hSize, c.vSize)
-	for y := 0; y < c.vSize; y++ {
-		for x := 0; x < c.hSize; x++ {
-			r := c.CastRayIntoPixel(x, y)
-			color := w.ColorAtIntersection(r)
-			canvas.WritePixel(x, y, color)
+	tiles := make(chan renderTile)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.Workers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each pixel is written by exactly one goroutine, so no locking is needed
+			for tile := range tiles {
+				c.renderTile(w, &canvas, tile)
+			}
+		}()
+	}
+
+	for y := 0; y < c.vSize; y += RENDER_TILE_SIZE {
+		for x := 0; x < c.hSize; x += RENDER_TILE_SIZE {
+			tiles <- renderTile{
+				x0: x,
+				y0: y,
+				x1: int(math.Min(float64(x+RENDER_TILE_SIZE), float64(c.hSize))),
+				y1: int(math.Min(float64(y+RENDER_TILE_SIZE), float64(c.vSize))),
+			}
 		}
 	}
+	close(tiles)
+	wg.Wait()
+
 	return canvas
 }
@@ -2,6 +2,7 @@ package ray_tracer
 
 import (
 	"math"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -33,33 +34,33 @@ func TestConstructingARayThroughTheCenterOfTheCanvas(t *testing.T) {
 	c := NewCamera(201, 101, math.Pi/2)
 	r := c.CastRayIntoPixel(100, 50)
 
-	require.True(t, r.origin.Equal(NewPoint(0, 0, 0)))
-	require.True(t, r.direction.Equal(NewVector(0, 0, -1)))
+	require.True(t, r.origin.Equal(NewPoint3(0, 0, 0)))
+	require.True(t, r.direction.Equal(NewVec3(0, 0, -1)))
 }
 
 func TestConstructingARayThorughACornerOfTheCanvas(t *testing.T) {
 	c := NewCamera(201, 101, math.Pi/2)
 	r := c.CastRayIntoPixel(0, 0)
 
-	require.True(t, r.origin.Equal(NewPoint(0, 0, 0)))
-	require.True(t, r.direction.Equal(NewVector(0.66519, 0.33259, -0.66851)))
+	require.True(t, r.origin.Equal(NewPoint3(0, 0, 0)))
+	require.True(t, r.direction.Equal(NewVec3(0.66519, 0.33259, -0.66851)))
 }
 
 func TestConstructingARayWhenTheCameraIsTransformed(t *testing.T) {
 	c := NewCamera(201, 101, math.Pi/2)
 	transform := NewRotationYMatrix(math.Pi / 4).MulMat(NewTranslationMatrix(0, -2, 5))
-	c.transform = *transform
+	c.SetTransform(transform)
 	r := c.CastRayIntoPixel(100, 50)
 
-	require.True(t, r.origin.Equal(NewPoint(0, 2, -5)))
-	require.True(t, r.direction.Equal(NewVector(COS45, 0, -COS45)))
+	require.True(t, r.origin.Equal(NewPoint3(0, 2, -5)))
+	require.True(t, r.direction.Equal(NewVec3(COS45, 0, -COS45)))
 }
 
 func TestRenderingAWorldWithACamera(t *testing.T) {
 	w := NewDefaultWorld()
 	c := NewCamera(11, 11, math.Pi/2)
-	from, to, up := NewPoint(0, 0, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0)
-	c.transform = *NewViewTransformation(from, to, up)
+	from, to, up := NewPoint3(0, 0, -5), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
 	image := c.Render(w)
 
 	expect := NewColor(0.38066, 0.47583, 0.2855)
@@ -67,3 +68,320 @@ func TestRenderingAWorldWithACamera(t *testing.T) {
 
 	require.True(t, expect.Equal(res))
 }
+
+func TestCameraUsesGomaxprocsWorkersByDefault(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2)
+
+	require.Equal(t, runtime.GOMAXPROCS(0), c.Workers())
+
+	c.SetWorkers(3)
+	require.Equal(t, 3, c.Workers())
+}
+
+func TestParallelRenderingIsIdenticalToSerial(t *testing.T) {
+	w := createWorldWithObjects08()
+	c := NewCamera(50, 37, math.Pi/3)
+	from, to, up := NewPoint3(0, 1.5, -5), NewPoint3(0, 1, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	expect := c.RenderSerial(w)
+
+	for _, workers := range []int{1, 2, 7} {
+		c.SetWorkers(workers)
+
+		image := c.Render(w)
+
+		require.Equal(t, expect, image, "%d workers", workers)
+	}
+}
+
+func TestCameraFollowsReflectionsUpToTheDefaultDepth(t *testing.T) {
+	c := NewCamera(160, 120, math.Pi/2)
+	require.Equal(t, MAX_REFLECTION_DEPTH, c.ReflectionDepth())
+
+	c.SetReflectionDepth(0)
+	require.Equal(t, 0, c.ReflectionDepth())
+}
+
+func TestRayThroughAnOffsetOfThePixel(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+
+	require.Equal(t, c.CastRayIntoPixel(0, 0), c.CastRayThroughPixel(0, 0, 0.5, 0.5))
+	// the right edge of the pixel is the left edge of the next one
+	require.Equal(t, c.CastRayThroughPixel(1, 0, 0, 0.5), c.CastRayThroughPixel(0, 0, 1, 0.5))
+}
+
+func TestCameraCastsOneRayPerPixelByDefault(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2)
+
+	require.Equal(t, 1, c.SamplesPerPixel())
+	require.Equal(t, SAMPLING_REGULAR, c.Sampling())
+	require.Equal(t, FILTER_BOX, c.Filter())
+	require.Panics(t, func() { c.SetSamplesPerPixel(0) })
+}
+
+func TestAntiAliasedRenderingAveragesSamples(t *testing.T) {
+	w := NewDefaultWorld()
+	c := NewCamera(11, 11, math.Pi/2)
+	from, to, up := NewPoint3(0, 0, -5), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	c.SetSamplesPerPixel(4)
+	image := c.Render(w)
+
+	expect := BLACK
+	for _, offset := range [][2]float64{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}} {
+		r := c.CastRayThroughPixel(5, 5, offset[0], offset[1])
+		expect = expect.Add(w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH).MultScalar(0.25))
+	}
+	require.True(t, expect.Equal(image.PixelAt(5, 5)))
+}
+
+func TestAntiAliasedRenderingIsReproducible(t *testing.T) {
+	w := NewDefaultWorld()
+	c := NewCamera(20, 20, math.Pi/3)
+	from, to, up := NewPoint3(0, 0, -5), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	c.SetSamplesPerPixel(5)
+
+	for _, sampling := range []PixelSampling{SAMPLING_REGULAR, SAMPLING_JITTERED, SAMPLING_HALTON, SAMPLING_SOBOL} {
+		for _, filter := range []PixelFilter{FILTER_BOX, FILTER_TENT, FILTER_GAUSSIAN, FILTER_MITCHELL} {
+			c.SetSampling(sampling)
+			c.SetFilter(filter)
+			c.SetSeed(42)
+			c.SetWorkers(3)
+			expect := c.RenderSerial(w)
+
+			require.Equal(t, expect, c.Render(w), "sampling %d, filter %d", sampling, filter)
+		}
+	}
+
+	c.SetSampling(SAMPLING_JITTERED)
+	c.SetSeed(1)
+	first := c.Render(w)
+	c.SetSeed(2)
+	require.NotEqual(t, first, c.Render(w))
+}
+
+func TestCameraIsAPinholeByDefault(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+
+	require.Equal(t, 0., c.Aperture())
+	require.Equal(t, 1., c.FocalDistance())
+	require.Panics(t, func() { c.SetAperture(-1) })
+	require.Panics(t, func() { c.SetFocalDistance(0) })
+}
+
+func TestRaysThroughTheLensConvergeOnTheFocalPlane(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+	transform := NewRotationYMatrix(math.Pi / 4).MulMat(NewTranslationMatrix(0, -2, 5))
+	c.SetTransform(transform)
+	c.SetAperture(0.5)
+	c.SetFocalDistance(3)
+
+	// the center pixel looks straight ahead, so the focal plane is 3 units along the ray
+	center := c.CastRayThroughLens(100, 50, 0.5, 0.5, 0.5, 0.5)
+	require.Equal(t, c.CastRayIntoPixel(100, 50), center)
+
+	focus := center.origin.Add(center.direction.Mul(3))
+	for _, lens := range [][2]float64{{0, 0}, {1, 0.5}, {0.2, 0.9}} {
+		r := c.CastRayThroughLens(100, 50, 0.5, 0.5, lens[0], lens[1])
+
+		require.False(t, r.origin.Equal(center.origin))
+		// the ray goes through the same point of the focal plane
+		toFocus := focus.Sub(r.origin)
+		require.True(t, toFocus.Normalize().Equal(r.direction), "lens %v", lens)
+		// and starts on the lens
+		require.True(t, r.origin.Sub(center.origin).Magnitude() <= 0.25+EPSILON)
+	}
+}
+
+func TestDepthOfFieldBlursOnlyOutOfFocusObjects(t *testing.T) {
+	w := NewDefaultWorld()
+	c := NewCamera(21, 21, math.Pi/3)
+	from, to, up := NewPoint3(0, 0, -5), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	c.SetSamplesPerPixel(16)
+	c.SetSeed(7)
+	sharp := c.Render(w)
+
+	difference := func(image Canvas) (sum float64) {
+		for y := 0; y < image.height; y++ {
+			for x := 0; x < image.width; x++ {
+				d := image.PixelAt(x, y).Sub(sharp.PixelAt(x, y))
+				sum += math.Abs(d.r) + math.Abs(d.g) + math.Abs(d.b)
+			}
+		}
+		return sum
+	}
+
+	c.SetAperture(0.3)
+	// the outline of the sphere is about 4.9 units away
+	c.SetFocalDistance(4.9)
+	focused := c.Render(w)
+	c.SetFocalDistance(20)
+	defocused := c.Render(w)
+
+	require.Greater(t, difference(defocused), 5*difference(focused))
+	require.Equal(t, defocused, c.RenderSerial(w))
+}
+
+func TestCameraUsesPerspectiveProjectionByDefault(t *testing.T) {
+	c := NewCamera(200, 100, math.Pi/2)
+
+	require.Equal(t, PROJECTION_PERSPECTIVE, c.Projection())
+	require.InDelta(t, 2, c.OrthographicWidth(), EPSILON)
+	require.Panics(t, func() { c.SetOrthographicWidth(0) })
+}
+
+func TestOrthographicRaysAreParallel(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+	transform := NewRotationYMatrix(math.Pi / 4).MulMat(NewTranslationMatrix(0, -2, 5))
+	c.SetTransform(transform)
+	c.SetProjection(PROJECTION_ORTHOGRAPHIC)
+	c.SetOrthographicWidth(20.1)
+
+	center := c.CastRayIntoPixel(100, 50)
+	require.True(t, center.origin.Equal(NewPoint3(0, 2, -5)))
+	require.True(t, center.direction.Equal(NewVec3(COS45, 0, -COS45)))
+
+	// pixels are 0.1 units wide
+	corner := c.CastRayIntoPixel(0, 0)
+	require.True(t, corner.origin.Equal(center.origin.Add(NewVec3(10*COS45, 5, 10*COS45))))
+	require.True(t, corner.direction.Equal(center.direction))
+}
+
+func TestFisheyeProjection(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi)
+	c.SetProjection(PROJECTION_FISHEYE)
+
+	tests := []struct {
+		px, py           int
+		offsetX, offsetY float64
+		direction        Vec3
+	}{
+		{100, 50, 0.5, 0.5, NewVec3(0, 0, -1)},
+		// the edge of the larger side is fieldOfView/2 away from the view direction
+		{0, 50, 0, 0.5, NewVec3(1, 0, 0)},
+		{200, 50, 1, 0.5, NewVec3(-1, 0, 0)},
+		// the angle grows linearly with the distance from the center
+		{100, 0, 0.5, 0, NewVec3(0, math.Sin(50.5/100.5*math.Pi/2), -math.Cos(50.5/100.5*math.Pi/2))},
+	}
+
+	for _, test := range tests {
+		r := c.CastRayThroughPixel(test.px, test.py, test.offsetX, test.offsetY)
+
+		require.True(t, r.origin.Equal(NewPoint3(0, 0, 0)))
+		require.True(t, r.direction.Equal(test.direction), "pixel (%d, %d): %v", test.px, test.py, r.direction)
+	}
+}
+
+func TestFisheyeImageIsClippedToCircle(t *testing.T) {
+	// every direction from inside of the room hits a lit wall
+	w := createClosedRoomWorld()
+	c := NewCamera(11, 11, math.Pi)
+	c.SetProjection(PROJECTION_FISHEYE)
+
+	for _, samples := range []int{1, 16} {
+		c.SetSamplesPerPixel(samples)
+
+		image := c.Render(w)
+
+		require.Equal(t, BLACK, image.PixelAt(0, 0), "%d samples", samples)
+		require.Equal(t, BLACK, image.PixelAt(10, 10), "%d samples", samples)
+		require.NotEqual(t, BLACK, image.PixelAt(5, 5), "%d samples", samples)
+		require.NotEqual(t, BLACK, image.PixelAt(0, 5), "%d samples", samples)
+	}
+}
+
+func TestEquirectangularProjection(t *testing.T) {
+	c := NewCamera(200, 100, math.Pi/2)
+	c.SetProjection(PROJECTION_EQUIRECTANGULAR)
+
+	tests := []struct {
+		px, py    int
+		direction Vec3
+	}{
+		{100, 50, NewVec3(0, 0, -1)},
+		{50, 50, NewVec3(1, 0, 0)},
+		{150, 50, NewVec3(-1, 0, 0)},
+		{0, 50, NewVec3(0, 0, 1)},
+		{100, 0, NewVec3(0, 1, 0)},
+		{100, 100, NewVec3(0, -1, 0)},
+	}
+
+	for _, test := range tests {
+		r := c.CastRayThroughPixel(test.px, test.py, 0, 0)
+
+		require.True(t, r.direction.Equal(test.direction), "pixel (%d, %d): %v", test.px, test.py, r.direction)
+	}
+}
+
+func TestAllProjectionsHonorTheViewTransform(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+	from, to, up := NewPoint3(1, 3, 2), NewPoint3(4, -2, 8), NewVec3(1, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	forward := to.Sub(from).Normalize()
+
+	for _, projection := range []Projection{PROJECTION_PERSPECTIVE, PROJECTION_ORTHOGRAPHIC, PROJECTION_FISHEYE, PROJECTION_EQUIRECTANGULAR} {
+		c.SetProjection(projection)
+
+		r := c.CastRayIntoPixel(100, 50)
+
+		require.True(t, r.origin.Equal(from), "projection %d", projection)
+		require.True(t, r.direction.Equal(forward), "projection %d: %v", projection, r.direction)
+	}
+}
+
+func TestCameraShutterIsClosedByDefault(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2)
+
+	open, close := c.Shutter()
+	require.Equal(t, 0., open)
+	require.Equal(t, 0., close)
+	require.Panics(t, func() { c.SetShutter(1, 0) })
+}
+
+func TestMovingShapeIsBlurredAlongItsPath(t *testing.T) {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(-10, 10, -10), WHITE))
+	s := NewDefaultSphere()
+	s.SetMotion(NewKeyframe(0, NewTranslationMatrix(-2, 0, 0)), NewKeyframe(1, NewTranslationMatrix(2, 0, 0)))
+	w.Add("s", &s)
+
+	c := NewCamera(21, 11, math.Pi/2)
+	from, to, up := NewPoint3(0, 0, -5), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	c.SetSamplesPerPixel(16)
+	c.SetShutter(0.5, 0.5)
+	still := c.Render(w)
+	c.SetShutter(0, 1)
+	blurred := c.Render(w)
+
+	// the sphere passes the center and the side only for a part of the exposure
+	require.NotEqual(t, still.PixelAt(10, 5), blurred.PixelAt(10, 5))
+	require.NotEqual(t, BLACK, blurred.PixelAt(10, 5))
+	require.Equal(t, BLACK, still.PixelAt(5, 5))
+	require.NotEqual(t, BLACK, blurred.PixelAt(5, 5))
+	require.Equal(t, blurred, c.RenderSerial(w))
+}
+
+func TestCameraUsesWhittedIntegratorByDefault(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2)
+
+	require.Equal(t, INTEGRATOR_WHITTED, c.Integrator())
+}
+
+func TestPathTracedRenderingIsReproducible(t *testing.T) {
+	w := createColorBleedingWorld()
+	c := NewCamera(16, 12, math.Pi/3)
+	from, to, up := NewPoint3(0, 2, -5), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
+	c.SetTransform(NewViewTransformation(from, to, up))
+	whitted := c.Render(w)
+
+	c.SetIntegrator(INTEGRATOR_PATH_TRACING)
+	c.SetSamplesPerPixel(4)
+	c.SetSeed(5)
+	image := c.Render(w)
+
+	require.NotEqual(t, whitted, image)
+	require.Equal(t, image, c.RenderSerial(w))
+}
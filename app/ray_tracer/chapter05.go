@@ -16,16 +16,12 @@ func Chapter05(filename string) {
 	transform := NewIdentityMatrix(4).Scale(scale, scale, scale).Translate(eyeCenterXY, eyeCenterXY, 0)
 	s.SetTransform(transform)
 
-	eyeOrigin := NewPoint(eyeCenterXY, eyeCenterXY, 100)
+	eyeOrigin := NewPoint3(eyeCenterXY, eyeCenterXY, 100)
 	hitCount := 0
 	for x := 0.; x < width; x++ {
 		for y := 0.; y < height; y++ {
-			targetCanvasPoint := NewPoint(x, y, 0)
+			targetCanvasPoint := NewPoint3(x, y, 0)
 			direction := targetCanvasPoint.Sub(eyeOrigin).Normalize()
-			if !direction.IsVector() {
-				panic("Directions should definitely be a vector!")
-			}
-
 			ray := NewRay(eyeOrigin, direction)
 			intersections := s.IntersectWith(&ray)
 			_, ok := Hit(intersections)
@@ -50,7 +46,7 @@ func Chapter05BookSolution(filename string) {
 
 	s := NewDefaultSphere()
 
-	eyeOrigin := NewPoint(0, 0, -5)
+	eyeOrigin := NewPoint3(0, 0, -5)
 	const wallZ, wallSize = 10, 7
 	const pixelSize = float64(wallSize) / canvasSize
 	const halfWall = wallSize / 2.
@@ -63,7 +59,7 @@ func Chapter05BookSolution(filename string) {
 		for x := 0.; x < canvasSize; x++ {
 			// compute the world x coordinate (left = -half, right = half)
 			worldX := -halfWall + pixelSize*x
-			targetPoint := NewPoint(worldX, worldY, wallZ)
+			targetPoint := NewPoint3(worldX, worldY, wallZ)
 			direction := targetPoint.Sub(eyeOrigin).Normalize()
 			ray := NewRay(eyeOrigin, direction)
 			xs := s.IntersectWith(&ray)
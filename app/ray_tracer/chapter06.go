@@ -14,19 +14,15 @@ func Chapter06LightAndShading(filename string) {
 	transform := NewIdentityMatrix(4).Scale(scale, scale, scale).Translate(eyeCenterXY, eyeCenterXY, 0)
 	sphere.SetTransform(transform)
 
-	lightPosition := NewPoint(eyeCenterXY+30, eyeCenterXY+30, 80)
+	lightPosition := NewPoint3(eyeCenterXY+30, eyeCenterXY+30, 80)
 	light := NewPointLight(lightPosition, WHITE)
 
-	eyeOrigin := NewPoint(eyeCenterXY, eyeCenterXY, 100)
+	eyeOrigin := NewPoint3(eyeCenterXY, eyeCenterXY, 100)
 	hitCount := 0
 	for x := 0.; x < width; x++ {
 		for y := 0.; y < height; y++ {
-			targetCanvasPoint := NewPoint(x, y, 0)
+			targetCanvasPoint := NewPoint3(x, y, 0)
 			direction := targetCanvasPoint.Sub(eyeOrigin).Normalize()
-			if !direction.IsVector() {
-				panic("Directions should definitely be a vector!")
-			}
-
 			ray := NewRay(eyeOrigin, direction)
 			intersections := sphere.IntersectWith(&ray)
 			hit, ok := Hit(intersections)
@@ -39,7 +35,7 @@ func Chapter06LightAndShading(filename string) {
 			normal := sphere.NormalAt(intersectionPoint)
 			eye := ray.direction.Mul(-1)
 			isInShadow := false
-			colorOnTheSphere := CalcLighting(m, light, intersectionPoint, eye, normal, isInShadow)
+			colorOnTheSphere := CalcLighting(m, &sphere, light, intersectionPoint, eye, normal, isInShadow)
 
 			shadowX, shadowY := canvas.ToCanvasCoordinates(x, y)
 			canvas.WritePixel(shadowX, shadowY, colorOnTheSphere)
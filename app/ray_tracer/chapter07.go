@@ -2,28 +2,32 @@ package ray_tracer
 
 import "math"
 
-func createWorldWithObjects() World {
+func createWorldWithObjects() *World {
 	flatScaling := *NewScalingMatrix(10, 0.01, 10)
 
 	w := NewWorld()
 
 	floor := NewDefaultSphere()
-	floor.transform = flatScaling
+	floor.SetTransform(&flatScaling)
 	floor.material = NewDefaultMaterial()
 	floor.material.color = NewColor(1, 0.9, 0.9)
 	floor.material.specular = 0
-	w["floor"] = &floor
+	floorPattern := NewCheckerPattern(floor.material.color, NewColor(0.5, 0.45, 0.45))
+	// undo the flattening of the spheres, so the checkers are unit squares in the world
+	floorPattern.SetTransform(NewScalingMatrix(0.1, 100, 0.1))
+	floor.material.pattern = floorPattern
+	w.Add("floor", &floor)
 
 	leftWall := NewDefaultSphere()
 	// transformations are applied in reverse order
-	leftWall.transform = *NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(-math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)).MulMat(&flatScaling)
+	leftWall.SetTransform(NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(-math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)).MulMat(&flatScaling))
 	leftWall.material = floor.material
-	w["leftWall"] = &leftWall
+	w.Add("leftWall", &leftWall)
 
 	rightWall := NewDefaultSphere()
-	rightWall.transform = *NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)).MulMat(&flatScaling)
+	rightWall.SetTransform(NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)).MulMat(&flatScaling))
 	rightWall.material = floor.material
-	w["rightWall"] = &rightWall
+	w.Add("rightWall", &rightWall)
 
 	sphereMaterial := NewDefaultMaterial()
 	sphereMaterial.color = GREEN
@@ -31,22 +35,22 @@ func createWorldWithObjects() World {
 	sphereMaterial.specular = 0.3
 
 	middleSphere := NewDefaultSphere()
-	middleSphere.transform = *NewTranslationMatrix(-0.5, 1, 0.5)
+	middleSphere.SetTransform(NewTranslationMatrix(-0.5, 1, 0.5))
 	middleSphere.material = sphereMaterial
-	w["middleSphere"] = &middleSphere
+	w.Add("middleSphere", &middleSphere)
 
 	rightSphere := NewDefaultSphere()
-	rightSphere.transform = *NewTranslationMatrix(1.5, 0.5, -0.5).MulMat(NewScalingMatrix(0.5, 0.5, 0.5))
+	rightSphere.SetTransform(NewTranslationMatrix(1.5, 0.5, -0.5).MulMat(NewScalingMatrix(0.5, 0.5, 0.5)))
 	rightSphere.material = sphereMaterial
-	w["rightSphere"] = &rightSphere
+	w.Add("rightSphere", &rightSphere)
 
 	leftSphere := NewDefaultSphere()
-	leftSphere.transform = *NewTranslationMatrix(-1.5, 0.33, -0.75).MulMat(NewScalingMatrix(0.33, 0.33, 0.33))
+	leftSphere.SetTransform(NewTranslationMatrix(-1.5, 0.33, -0.75).MulMat(NewScalingMatrix(0.33, 0.33, 0.33)))
 	leftSphere.material = sphereMaterial
 	leftSphere.material.color = NewColor(1, 0.8, 0.1)
-	w["leftSphere"] = &leftSphere
+	w.Add("leftSphere", &leftSphere)
 
-	w.SetLight(NewPointLight(NewPoint(-10, 10, -10), WHITE))
+	w.SetLight(NewPointLight(NewPoint3(-10, 10, -10), WHITE))
 	return w
 }
 
@@ -55,8 +59,8 @@ func Chapter07MakingAScene(filename string) {
 
 	// Change camera size to get a better resolution
 	camera := NewCamera(60, 30, math.Pi/3)
-	from, to, up := NewPoint(0, 1.5, -5), NewPoint(0, 1, 0), NewVector(0, 1, 0)
-	camera.transform = *NewViewTransformation(from, to, up)
+	from, to, up := NewPoint3(0, 1.5, -5), NewPoint3(0, 1, 0), NewVec3(0, 1, 0)
+	camera.SetTransform(NewViewTransformation(from, to, up))
 
 	canvas := camera.Render(w)
 	canvas.SavePpm(filename)
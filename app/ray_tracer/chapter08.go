@@ -2,28 +2,26 @@ package ray_tracer
 
 import "math"
 
-func createWorldWithObjects08() World {
-	flatScaling := *NewScalingMatrix(10, 0.01, 10)
-
+func createWorldWithObjects08() *World {
 	w := NewWorld()
 
-	floor := NewDefaultSphere()
-	floor.transform = flatScaling
+	floor := NewDefaultPlane()
 	floor.material = NewDefaultMaterial()
 	floor.material.color = NewColor(1, 0.9, 0.9)
 	floor.material.specular = 0
-	w["floor"] = &floor
+	floor.material.pattern = NewCheckerPattern(floor.material.color, NewColor(0.5, 0.45, 0.45))
+	w.Add("floor", &floor)
 
-	leftWall := NewDefaultSphere()
+	leftWall := NewDefaultPlane()
 	// transformations are applied in reverse order
-	leftWall.transform = *NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(-math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)).MulMat(&flatScaling)
+	leftWall.SetTransform(NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(-math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)))
 	leftWall.material = floor.material
-	w["leftWall"] = &leftWall
+	w.Add("leftWall", &leftWall)
 
-	rightWall := NewDefaultSphere()
-	rightWall.transform = *NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)).MulMat(&flatScaling)
+	rightWall := NewDefaultPlane()
+	rightWall.SetTransform(NewTranslationMatrix(0, 0, 5).MulMat(NewRotationYMatrix(math.Pi / 4)).MulMat(NewRotationXMatrix(math.Pi / 2)))
 	rightWall.material = floor.material
-	w["rightWall"] = &rightWall
+	w.Add("rightWall", &rightWall)
 
 	sphereMaterial := NewDefaultMaterial()
 	sphereMaterial.color = WHITE
@@ -31,22 +29,26 @@ func createWorldWithObjects08() World {
 	sphereMaterial.specular = 0.01
 
 	middleSphere := NewDefaultSphere()
-	middleSphere.transform = *NewTranslationMatrix(-0.5, 1, 0.5)
+	middleSphere.SetTransform(NewTranslationMatrix(-0.5, 1, 0.5))
 	middleSphere.material = sphereMaterial
 	middleSphere.material.color = RED
-	w["middleSphere"] = &middleSphere
+	w.Add("middleSphere", &middleSphere)
 
 	rightSphere := NewDefaultSphere()
-	rightSphere.transform = *NewTranslationMatrix(1.5, 0.5, -0.5).MulMat(NewScalingMatrix(0.5, 0.5, 0.5))
+	rightSphere.SetTransform(NewTranslationMatrix(1.5, 0.5, -0.5).MulMat(NewScalingMatrix(0.5, 0.5, 0.5)))
 	rightSphere.material = sphereMaterial
-	w["rightSphere"] = &rightSphere
+	w.Add("rightSphere", &rightSphere)
 
 	leftSphere := NewDefaultSphere()
-	leftSphere.transform = *NewTranslationMatrix(-1.5, 0.33, -0.75).MulMat(NewScalingMatrix(0.33, 0.33, 0.33))
+	leftSphere.SetTransform(NewTranslationMatrix(-1.5, 0.33, -0.75).MulMat(NewScalingMatrix(0.33, 0.33, 0.33)))
 	leftSphere.material = sphereMaterial
-	w["leftSphere"] = &leftSphere
+	w.Add("leftSphere", &leftSphere)
 
-	w.SetLight(NewPointLight(NewPoint(-10, 10, -10), WHITE))
+	// 2x2 area light centered at the same point as the point light used to be,
+	// so the shadows have soft edges
+	light := NewAreaLight(NewPoint3(-11, 9, -10), NewVec3(2, 0, 0), 4, NewVec3(0, 2, 0), 4, WHITE)
+	light.SetJitter(true)
+	w.AddLight("light", light)
 	return w
 }
 
@@ -55,8 +57,8 @@ func Chapter08Shadows(filename string) {
 
 	// Change camera size to get a better resolution
 	camera := NewCamera(1200, 800, math.Pi/3)
-	from, to, up := NewPoint(0, 1.5, -5), NewPoint(0, 1, 0), NewVector(0, 1, 0)
-	camera.transform = *NewViewTransformation(from, to, up)
+	from, to, up := NewPoint3(0, 1.5, -5), NewPoint3(0, 1, 0), NewVec3(0, 1, 0)
+	camera.SetTransform(NewViewTransformation(from, to, up))
 
 	canvas := camera.Render(w)
 	canvas.SavePpm(filename)
@@ -68,3 +68,28 @@ func TestChapter07MakingAScene(t *testing.T) {
 
 	cleanup(filename)
 }
+
+func TestChapter08WorldHasFlatFloorAndWalls(t *testing.T) {
+	w := createWorldWithObjects08()
+
+	for _, name := range []string{"floor", "leftWall", "rightWall"} {
+		_, ok := w.Object(name).(*Plane)
+		require.True(t, ok, "%q should be a plane", name)
+	}
+}
+
+func TestFloorAndWallsOfTestScenesAreCheckered(t *testing.T) {
+	for _, w := range []*World{createWorldWithObjects(), createWorldWithObjects08()} {
+		for _, name := range []string{"floor", "leftWall", "rightWall"} {
+			_, ok := w.Object(name).(Shape).Material().pattern.(*CheckerPattern)
+			require.True(t, ok, "%q should have checkers", name)
+		}
+	}
+}
+
+func TestChapter08WorldHasSoftShadows(t *testing.T) {
+	w := createWorldWithObjects08()
+
+	_, ok := w.Light().(AreaLight)
+	require.True(t, ok)
+}
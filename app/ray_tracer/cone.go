@@ -0,0 +1,84 @@
+package ray_tracer
+
+import "math"
+
+// Double-napped cone around the Y axis with the apex in (0,0,0). Radius at any Y equals |Y|.
+// Just like a cylinder it may be truncated at minimum/maximum Y and closed with caps.
+type Cone struct {
+	shape
+	minimum float64
+	maximum float64
+	closed  bool
+}
+
+func NewCone(id string, material Material) Cone {
+	return Cone{
+		shape:   newShape(id, material),
+		minimum: math.Inf(-1),
+		maximum: math.Inf(1),
+		closed:  false,
+	}
+}
+
+func NewDefaultCone() Cone {
+	return NewCone("cone_id", NewDefaultMaterial())
+}
+
+func (cone *Cone) Truncate(minimum, maximum float64, closed bool) {
+	cone.minimum, cone.maximum, cone.closed = minimum, maximum, closed
+}
+
+func (cone *Cone) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(cone, r)
+}
+
+func (cone *Cone) NormalAt(worldPoint Point3) Vec3 {
+	return NormalAt(cone, worldPoint, Intersection{})
+}
+
+func (cone *Cone) localIntersectWith(r *Ray) []Intersection {
+	xs := []Intersection{}
+
+	o, d := r.origin, r.direction
+	a := d.x*d.x - d.y*d.y + d.z*d.z
+	b := 2*o.x*d.x - 2*o.y*d.y + 2*o.z*d.z
+	c := o.x*o.x - o.y*o.y + o.z*o.z
+
+	if math.Abs(a) >= EPSILON {
+		xs = append(xs, intersectWalls(cone, r, a, b, c, cone.minimum, cone.maximum)...)
+	} else if math.Abs(b) >= EPSILON {
+		// ray is parallel to one of the cone's halves, so it hits only the other half once
+		t := -c / (2 * b)
+		y := o.y + t*d.y
+		if cone.minimum < y && y < cone.maximum {
+			xs = append(xs, NewIntersection(t, cone))
+		}
+	}
+
+	if cone.closed {
+		radiusAt := func(y float64) float64 { return math.Abs(y) }
+		xs = append(xs, intersectCaps(cone, r, cone.minimum, cone.maximum, radiusAt)...)
+	}
+	return xs
+}
+
+func (cone *Cone) Bounds() BoundingBox {
+	limit := math.Max(math.Abs(cone.minimum), math.Abs(cone.maximum))
+	return NewBoundingBox(NewPoint3(-limit, cone.minimum, -limit), NewPoint3(limit, cone.maximum, limit))
+}
+
+func (cone *Cone) localNormalAt(point Point3, hit Intersection) Vec3 {
+	distance := point.x*point.x + point.z*point.z
+
+	if distance < point.y*point.y && point.y >= cone.maximum-EPSILON {
+		return NewVec3(0, 1, 0)
+	} else if distance < point.y*point.y && point.y <= cone.minimum+EPSILON {
+		return NewVec3(0, -1, 0)
+	}
+
+	y := math.Sqrt(distance)
+	if point.y > 0 {
+		y = -y
+	}
+	return NewVec3(point.x, y, point.z)
+}
@@ -0,0 +1,89 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntersectingConeWithRay(t *testing.T) {
+	cone := NewDefaultCone()
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+		t0, t1    float64
+	}{
+		{NewPoint3(0, 0, -5), NewVec3(0, 0, 1), 5, 5},
+		{NewPoint3(0, 0, -5), NewVec3(1, 1, 1), 8.66025, 8.66025},
+		{NewPoint3(1, 1, -5), NewVec3(-0.5, -1, 1), 4.55006, 49.44994},
+	}
+
+	for _, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		xs := cone.localIntersectWith(&r)
+
+		require.Len(t, xs, 2, "ray from %v", tc.origin)
+		require.InDelta(t, tc.t0, xs[0].time, EPSILON)
+		require.InDelta(t, tc.t1, xs[1].time, EPSILON)
+	}
+}
+
+func TestIntersectingConeWithRayParallelToOneOfItsHalves(t *testing.T) {
+	cone := NewDefaultCone()
+	r := NewRay(NewPoint3(0, 0, -1), NewVec3(0, 1, 1).Normalize())
+
+	xs := cone.localIntersectWith(&r)
+
+	require.Len(t, xs, 1)
+	require.InDelta(t, 0.35355, xs[0].time, EPSILON)
+}
+
+func TestIntersectingConesEndCaps(t *testing.T) {
+	cone := NewDefaultCone()
+	cone.Truncate(-0.5, 0.5, true)
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+		count     int
+	}{
+		{NewPoint3(0, 0, -5), NewVec3(0, 1, 0), 0},
+		{NewPoint3(0, 0, -0.25), NewVec3(0, 1, 1), 2},
+		{NewPoint3(0, 0, -0.25), NewVec3(0, 1, 0), 4},
+	}
+
+	for i, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		xs := cone.localIntersectWith(&r)
+
+		require.Len(t, xs, tc.count, "case %d", i+1)
+	}
+}
+
+func TestNormalOnCone(t *testing.T) {
+	cone := NewDefaultCone()
+	testCases := []struct {
+		point  Point3
+		normal Vec3
+	}{
+		{NewPoint3(0, 0, 0), NewVec3(0, 0, 0)},
+		{NewPoint3(1, 1, 1), NewVec3(1, -math.Sqrt(2), 1)},
+		{NewPoint3(-1, -1, 0), NewVec3(-1, 1, 0)},
+	}
+
+	for _, tc := range testCases {
+		n := cone.localNormalAt(tc.point, Intersection{})
+
+		require.True(t, n.Equal(tc.normal), "normal at %v is %v", tc.point, n)
+	}
+}
+
+func TestNormalOnConeCaps(t *testing.T) {
+	cone := NewDefaultCone()
+	cone.Truncate(-1, 2, true)
+
+	require.True(t, cone.localNormalAt(NewPoint3(0.5, 2, 0), Intersection{}).Equal(NewVec3(0, 1, 0)))
+	require.True(t, cone.localNormalAt(NewPoint3(0.5, -1, 0), Intersection{}).Equal(NewVec3(0, -1, 0)))
+}
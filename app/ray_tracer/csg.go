@@ -0,0 +1,122 @@
+package ray_tracer
+
+import (
+	"fmt"
+	"sort"
+)
+
+type CsgOperation int
+
+const (
+	// Everything which is inside of either of the shapes
+	CSG_UNION CsgOperation = iota
+	// Only the part which is inside of both shapes
+	CSG_INTERSECTION
+	// Left shape with the right shape "carved out" of it
+	CSG_DIFFERENCE
+)
+
+// Constructive solid geometry: a shape made by combining 2 other shapes with
+// union, intersection or difference operation
+type CSG struct {
+	shape
+	operation CsgOperation
+	left      Shape
+	right     Shape
+}
+
+// Returns a pointer, because left and right shapes need to know their parent
+func NewCSG(id string, operation CsgOperation, left, right Shape) *CSG {
+	csg := &CSG{
+		shape:     newShape(id, NewDefaultMaterial()),
+		operation: operation,
+		left:      left,
+		right:     right,
+	}
+	left.setParent(csg)
+	right.setParent(csg)
+	return csg
+}
+
+// CSG itself is never drawn, so its material only matters as a way
+// to paint both shapes at once
+func (csg *CSG) SetMaterial(m Material) {
+	csg.material = m
+	csg.left.SetMaterial(m)
+	csg.right.SetMaterial(m)
+}
+
+func (csg *CSG) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(csg, r)
+}
+
+func (csg *CSG) localIntersectWith(r *Ray) []Intersection {
+	xs := append(IntersectWith(csg.left, r), IntersectWith(csg.right, r)...)
+	sort.Slice(xs, func(i, j int) bool { return xs[i].time < xs[j].time })
+	return csg.filterIntersections(xs)
+}
+
+func (csg *CSG) Bounds() BoundingBox {
+	return parentSpaceBounds(csg.left).Merge(parentSpaceBounds(csg.right))
+}
+
+// Intersections never reference a CSG, but the shapes it's made of, so normal
+// is always calculated on them
+func (csg *CSG) localNormalAt(point Point3, hit Intersection) Vec3 {
+	panic(fmt.Sprintf("Normal can't be calculated on a CSG %q!", csg.id))
+}
+
+// Decides if the intersection should be kept:
+// leftHit - whether the left shape was hit (otherwise the right one was),
+// insideLeft - whether the hit is inside the left shape,
+// insideRight - whether the hit is inside the right shape
+func (op CsgOperation) intersectionAllowed(leftHit, insideLeft, insideRight bool) bool {
+	switch op {
+	case CSG_UNION:
+		return (leftHit && !insideRight) || (!leftHit && !insideLeft)
+	case CSG_INTERSECTION:
+		return (leftHit && insideRight) || (!leftHit && insideLeft)
+	case CSG_DIFFERENCE:
+		return (leftHit && !insideRight) || (!leftHit && insideLeft)
+	default:
+		panic(fmt.Sprintf("Unknown CSG operation %d!", op))
+	}
+}
+
+// Goes through the sorted intersections tracking if we're inside of the left and the
+// right shape. Every intersection flips being inside of the shape which was hit
+func (csg *CSG) filterIntersections(xs []Intersection) []Intersection {
+	insideLeft, insideRight := false, false
+	result := []Intersection{}
+
+	for _, i := range xs {
+		leftHit := includes(csg.left, i.object)
+		if csg.operation.intersectionAllowed(leftHit, insideLeft, insideRight) {
+			result = append(result, i)
+		}
+
+		if leftHit {
+			insideLeft = !insideLeft
+		} else {
+			insideRight = !insideRight
+		}
+	}
+	return result
+}
+
+// Checks if the shape is the other shape, or contains it (for groups and CSGs)
+func includes(s Shape, other Shape) bool {
+	switch s := s.(type) {
+	case *Group:
+		for _, child := range s.children {
+			if includes(child, other) {
+				return true
+			}
+		}
+		return false
+	case *CSG:
+		return includes(s.left, other) || includes(s.right, other)
+	default:
+		return s == other
+	}
+}
@@ -0,0 +1,153 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatingCSG(t *testing.T) {
+	s1 := NewDefaultSphere()
+	s2 := NewDefaultCube()
+
+	c := NewCSG("csg_id", CSG_UNION, &s1, &s2)
+
+	require.Equal(t, CSG_UNION, c.operation)
+	require.Equal(t, &s1, c.left)
+	require.Equal(t, &s2, c.right)
+	require.Equal(t, c, s1.Parent())
+	require.Equal(t, c, s2.Parent())
+}
+
+func TestEvaluatingRuleForCSGOperation(t *testing.T) {
+	testCases := []struct {
+		op                               CsgOperation
+		leftHit, insideLeft, insideRight bool
+		result                           bool
+	}{
+		{CSG_UNION, true, true, true, false},
+		{CSG_UNION, true, true, false, true},
+		{CSG_UNION, true, false, true, false},
+		{CSG_UNION, true, false, false, true},
+		{CSG_UNION, false, true, true, false},
+		{CSG_UNION, false, true, false, false},
+		{CSG_UNION, false, false, true, true},
+		{CSG_UNION, false, false, false, true},
+		{CSG_INTERSECTION, true, true, true, true},
+		{CSG_INTERSECTION, true, true, false, false},
+		{CSG_INTERSECTION, true, false, true, true},
+		{CSG_INTERSECTION, true, false, false, false},
+		{CSG_INTERSECTION, false, true, true, true},
+		{CSG_INTERSECTION, false, true, false, true},
+		{CSG_INTERSECTION, false, false, true, false},
+		{CSG_INTERSECTION, false, false, false, false},
+		{CSG_DIFFERENCE, true, true, true, false},
+		{CSG_DIFFERENCE, true, true, false, true},
+		{CSG_DIFFERENCE, true, false, true, false},
+		{CSG_DIFFERENCE, true, false, false, true},
+		{CSG_DIFFERENCE, false, true, true, true},
+		{CSG_DIFFERENCE, false, true, false, true},
+		{CSG_DIFFERENCE, false, false, true, false},
+		{CSG_DIFFERENCE, false, false, false, false},
+	}
+
+	for i, tc := range testCases {
+		res := tc.op.intersectionAllowed(tc.leftHit, tc.insideLeft, tc.insideRight)
+
+		require.Equal(t, tc.result, res, "case %d", i+1)
+	}
+}
+
+func TestFilteringListOfIntersections(t *testing.T) {
+	testCases := []struct {
+		op     CsgOperation
+		x0, x1 int
+	}{
+		{CSG_UNION, 0, 3},
+		{CSG_INTERSECTION, 1, 2},
+		{CSG_DIFFERENCE, 0, 1},
+	}
+
+	for _, tc := range testCases {
+		s1 := NewDefaultSphere()
+		s2 := NewDefaultCube()
+		c := NewCSG("csg_id", tc.op, &s1, &s2)
+		xs := []Intersection{
+			NewIntersection(1, &s1),
+			NewIntersection(2, &s2),
+			NewIntersection(3, &s1),
+			NewIntersection(4, &s2),
+		}
+
+		result := c.filterIntersections(xs)
+
+		require.Len(t, result, 2)
+		require.Equal(t, xs[tc.x0], result[0])
+		require.Equal(t, xs[tc.x1], result[1])
+	}
+}
+
+func TestRayMissesCSGObject(t *testing.T) {
+	s1 := NewDefaultSphere()
+	s2 := NewDefaultCube()
+	c := NewCSG("csg_id", CSG_UNION, &s1, &s2)
+	r := NewRay(NewPoint3(0, 2, -5), NewVec3(0, 0, 1))
+
+	xs := c.localIntersectWith(&r)
+
+	require.Len(t, xs, 0)
+}
+
+func TestRayHitsCSGObject(t *testing.T) {
+	s1 := NewSphere("s1", NewDefaultMaterial())
+	s2 := NewSphere("s2", NewDefaultMaterial())
+	s2.SetTransform(NewTranslationMatrix(0, 0, 0.5))
+	c := NewCSG("csg_id", CSG_UNION, &s1, &s2)
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+
+	xs := c.localIntersectWith(&r)
+
+	require.Len(t, xs, 2)
+	require.InDelta(t, 4, xs[0].time, EPSILON)
+	require.Equal(t, &s1, xs[0].object)
+	require.InDelta(t, 6.5, xs[1].time, EPSILON)
+	require.Equal(t, &s2, xs[1].object)
+}
+
+func TestCSGWithNestedGroupFiltersByContainingChild(t *testing.T) {
+	g := NewDefaultGroup()
+	s1 := NewSphere("s1", NewDefaultMaterial())
+	g.AddChild(&s1)
+	s2 := NewSphere("s2", NewDefaultMaterial())
+	s2.SetTransform(NewTranslationMatrix(0, 0, 0.5))
+	c := NewCSG("csg_id", CSG_DIFFERENCE, g, &s2)
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+
+	xs := c.localIntersectWith(&r)
+
+	require.Len(t, xs, 2)
+	require.InDelta(t, 4, xs[0].time, EPSILON)
+	require.Equal(t, &s1, xs[0].object)
+	require.InDelta(t, 4.5, xs[1].time, EPSILON)
+	require.Equal(t, &s2, xs[1].object)
+}
+
+func TestNormalOnCSGChildRespectsCSGTransform(t *testing.T) {
+	s1 := NewSphere("s1", NewDefaultMaterial())
+	s2 := NewSphere("s2", NewDefaultMaterial())
+	s2.SetTransform(NewTranslationMatrix(0, 0, 0.5))
+	c := NewCSG("csg_id", CSG_INTERSECTION, &s1, &s2)
+	c.SetTransform(NewTranslationMatrix(0, 0, 10))
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(0, 0, -10), WHITE))
+	w.Add("lens", c)
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+
+	xs := w.IntersectWith(&r)
+
+	require.Len(t, xs, 2)
+	require.InDelta(t, 9.5, xs[0].time, EPSILON)
+	require.Equal(t, &s2, xs[0].object)
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+	require.True(t, comps.objectNormalv.Equal(NewVec3(0, 0, -1)))
+}
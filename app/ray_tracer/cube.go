@@ -0,0 +1,79 @@
+package ray_tracer
+
+import "math"
+
+// Axis-aligned cube with a center in (0,0,0) and extending from -1 to 1 along each axis
+type Cube struct {
+	shape
+}
+
+func NewCube(id string, material Material) Cube {
+	return Cube{shape: newShape(id, material)}
+}
+
+func NewDefaultCube() Cube {
+	return NewCube("cube_id", NewDefaultMaterial())
+}
+
+func (c *Cube) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(c, r)
+}
+
+func (c *Cube) NormalAt(worldPoint Point3) Vec3 {
+	return NormalAt(c, worldPoint, Intersection{})
+}
+
+// Finds where the ray enters and leaves the slab between two parallel planes
+// at minimum and maximum along one of the axes
+func checkAxis(origin, direction, minimum, maximum float64) (tmin, tmax float64) {
+	tminNumerator := minimum - origin
+	tmaxNumerator := maximum - origin
+
+	if math.Abs(direction) >= EPSILON {
+		tmin = tminNumerator / direction
+		tmax = tmaxNumerator / direction
+	} else {
+		// ray is parallel to the slab, so it either is inside it all the time, or never
+		tmin = tminNumerator * math.Inf(1)
+		tmax = tmaxNumerator * math.Inf(1)
+	}
+
+	if tmin > tmax {
+		tmin, tmax = tmax, tmin
+	}
+	return tmin, tmax
+}
+
+// Cube is an intersection of 3 slabs. The ray hits the cube only if the largest of
+// the entering times is smaller than the smallest of the leaving times
+func (c *Cube) localIntersectWith(r *Ray) []Intersection {
+	xtmin, xtmax := checkAxis(r.origin.x, r.direction.x, -1, 1)
+	ytmin, ytmax := checkAxis(r.origin.y, r.direction.y, -1, 1)
+	ztmin, ztmax := checkAxis(r.origin.z, r.direction.z, -1, 1)
+
+	tmin := math.Max(xtmin, math.Max(ytmin, ztmin))
+	tmax := math.Min(xtmax, math.Min(ytmax, ztmax))
+
+	if tmin > tmax {
+		return []Intersection{}
+	}
+
+	return []Intersection{NewIntersection(tmin, c), NewIntersection(tmax, c)}
+}
+
+func (c *Cube) Bounds() BoundingBox {
+	return NewBoundingBox(NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1))
+}
+
+// The face is determined by the component with the largest absolute value
+func (c *Cube) localNormalAt(point Point3, hit Intersection) Vec3 {
+	absX, absY, absZ := math.Abs(point.x), math.Abs(point.y), math.Abs(point.z)
+	maxc := math.Max(absX, math.Max(absY, absZ))
+
+	if maxc == absX {
+		return NewVec3(point.x, 0, 0)
+	} else if maxc == absY {
+		return NewVec3(0, point.y, 0)
+	}
+	return NewVec3(0, 0, point.z)
+}
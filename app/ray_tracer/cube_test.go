@@ -0,0 +1,93 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRayIntersectsCube(t *testing.T) {
+	c := NewDefaultCube()
+	testCases := []struct {
+		name      string
+		origin    Point3
+		direction Vec3
+		t1, t2    float64
+	}{
+		{"+x", NewPoint3(5, 0.5, 0), NewVec3(-1, 0, 0), 4, 6},
+		{"-x", NewPoint3(-5, 0.5, 0), NewVec3(1, 0, 0), 4, 6},
+		{"+y", NewPoint3(0.5, 5, 0), NewVec3(0, -1, 0), 4, 6},
+		{"-y", NewPoint3(0.5, -5, 0), NewVec3(0, 1, 0), 4, 6},
+		{"+z", NewPoint3(0.5, 0, 5), NewVec3(0, 0, -1), 4, 6},
+		{"-z", NewPoint3(0.5, 0, -5), NewVec3(0, 0, 1), 4, 6},
+		{"inside", NewPoint3(0, 0.5, 0), NewVec3(0, 0, 1), -1, 1},
+	}
+
+	for _, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction)
+
+		xs := c.localIntersectWith(&r)
+
+		require.Len(t, xs, 2, tc.name)
+		require.EqualValues(t, tc.t1, xs[0].time, tc.name)
+		require.EqualValues(t, tc.t2, xs[1].time, tc.name)
+	}
+}
+
+func TestRayMissesCube(t *testing.T) {
+	c := NewDefaultCube()
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+	}{
+		{NewPoint3(-2, 0, 0), NewVec3(0.2673, 0.5345, 0.8018)},
+		{NewPoint3(0, -2, 0), NewVec3(0.8018, 0.2673, 0.5345)},
+		{NewPoint3(0, 0, -2), NewVec3(0.5345, 0.8018, 0.2673)},
+		{NewPoint3(2, 0, 2), NewVec3(0, 0, -1)},
+		{NewPoint3(0, 2, 2), NewVec3(0, -1, 0)},
+		{NewPoint3(2, 2, 0), NewVec3(-1, 0, 0)},
+	}
+
+	for _, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction)
+
+		xs := c.localIntersectWith(&r)
+
+		require.Len(t, xs, 0, "ray from %v", tc.origin)
+	}
+}
+
+func TestNormalOnSurfaceOfCube(t *testing.T) {
+	c := NewDefaultCube()
+	testCases := []struct {
+		point  Point3
+		normal Vec3
+	}{
+		{NewPoint3(1, 0.5, -0.8), NewVec3(1, 0, 0)},
+		{NewPoint3(-1, -0.2, 0.9), NewVec3(-1, 0, 0)},
+		{NewPoint3(-0.4, 1, -0.1), NewVec3(0, 1, 0)},
+		{NewPoint3(0.3, -1, -0.7), NewVec3(0, -1, 0)},
+		{NewPoint3(-0.6, 0.3, 1), NewVec3(0, 0, 1)},
+		{NewPoint3(0.4, 0.4, -1), NewVec3(0, 0, -1)},
+		{NewPoint3(1, 1, 1), NewVec3(1, 0, 0)},
+		{NewPoint3(-1, -1, -1), NewVec3(-1, 0, 0)},
+	}
+
+	for _, tc := range testCases {
+		n := c.localNormalAt(tc.point, Intersection{})
+
+		require.True(t, n.Equal(tc.normal), "normal at %v is %v", tc.point, n)
+	}
+}
+
+func TestIntersectingTransformedCube(t *testing.T) {
+	c := NewDefaultCube()
+	c.SetTransform(NewTranslationMatrix(0, 0, 10).MulMat(NewScalingMatrix(2, 2, 2)))
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+
+	xs := c.IntersectWith(&r)
+
+	require.Len(t, xs, 2)
+	require.EqualValues(t, 8, xs[0].time)
+	require.EqualValues(t, 12, xs[1].time)
+}
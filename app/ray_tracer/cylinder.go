@@ -0,0 +1,122 @@
+package ray_tracer
+
+import "math"
+
+// Cylinder of radius 1 around the Y axis. By default it's infinitely long and open,
+// but may be truncated at minimum/maximum (exclusive) Y and closed with caps.
+type Cylinder struct {
+	shape
+	minimum float64
+	maximum float64
+	closed  bool
+}
+
+func NewCylinder(id string, material Material) Cylinder {
+	return Cylinder{
+		shape:   newShape(id, material),
+		minimum: math.Inf(-1),
+		maximum: math.Inf(1),
+		closed:  false,
+	}
+}
+
+func NewDefaultCylinder() Cylinder {
+	return NewCylinder("cylinder_id", NewDefaultMaterial())
+}
+
+func (cyl *Cylinder) Truncate(minimum, maximum float64, closed bool) {
+	cyl.minimum, cyl.maximum, cyl.closed = minimum, maximum, closed
+}
+
+func (cyl *Cylinder) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(cyl, r)
+}
+
+func (cyl *Cylinder) NormalAt(worldPoint Point3) Vec3 {
+	return NormalAt(cyl, worldPoint, Intersection{})
+}
+
+func (cyl *Cylinder) localIntersectWith(r *Ray) []Intersection {
+	xs := []Intersection{}
+
+	a := r.direction.x*r.direction.x + r.direction.z*r.direction.z
+	// a == 0 means the ray is parallel to the Y axis, so it can hit only the caps
+	if math.Abs(a) >= EPSILON {
+		b := 2*r.origin.x*r.direction.x + 2*r.origin.z*r.direction.z
+		c := r.origin.x*r.origin.x + r.origin.z*r.origin.z - 1
+		xs = append(xs, intersectWalls(cyl, r, a, b, c, cyl.minimum, cyl.maximum)...)
+	}
+
+	if cyl.closed {
+		unitRadius := func(y float64) float64 { return 1 }
+		xs = append(xs, intersectCaps(cyl, r, cyl.minimum, cyl.maximum, unitRadius)...)
+	}
+	return xs
+}
+
+func (cyl *Cylinder) Bounds() BoundingBox {
+	return NewBoundingBox(NewPoint3(-1, cyl.minimum, -1), NewPoint3(1, cyl.maximum, 1))
+}
+
+func (cyl *Cylinder) localNormalAt(point Point3, hit Intersection) Vec3 {
+	distance := point.x*point.x + point.z*point.z
+
+	if distance < 1 && point.y >= cyl.maximum-EPSILON {
+		return NewVec3(0, 1, 0)
+	} else if distance < 1 && point.y <= cyl.minimum+EPSILON {
+		return NewVec3(0, -1, 0)
+	}
+	return NewVec3(point.x, 0, point.z)
+}
+
+// Solves quadratic equation for the side walls of cylinders and cones and keeps only
+// intersections within the (minimum, maximum) range
+func intersectWalls(s Shape, r *Ray, a, b, c, minimum, maximum float64) []Intersection {
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return []Intersection{}
+	}
+
+	t0 := (-b - math.Sqrt(discriminant)) / (2 * a)
+	t1 := (-b + math.Sqrt(discriminant)) / (2 * a)
+	if t0 > t1 {
+		t0, t1 = t1, t0
+	}
+
+	xs := []Intersection{}
+	for _, t := range []float64{t0, t1} {
+		y := r.origin.y + t*r.direction.y
+		if minimum < y && y < maximum {
+			xs = append(xs, NewIntersection(t, s))
+		}
+	}
+	return xs
+}
+
+// Checks if the intersection at time t is within the radius of the cap
+func checkCap(r *Ray, t float64, radius float64) bool {
+	x := r.origin.x + t*r.direction.x
+	z := r.origin.z + t*r.direction.z
+	return x*x+z*z <= radius*radius
+}
+
+// Intersects the ray with the planes y == minimum and y == maximum, keeping only
+// hits inside the caps. radiusAt returns the radius of the cap at a given Y
+func intersectCaps(s Shape, r *Ray, minimum, maximum float64, radiusAt func(y float64) float64) []Intersection {
+	xs := []Intersection{}
+	// caps only matter if the ray can hit them
+	if math.Abs(r.direction.y) < EPSILON {
+		return xs
+	}
+
+	t := (minimum - r.origin.y) / r.direction.y
+	if checkCap(r, t, radiusAt(minimum)) {
+		xs = append(xs, NewIntersection(t, s))
+	}
+
+	t = (maximum - r.origin.y) / r.direction.y
+	if checkCap(r, t, radiusAt(maximum)) {
+		xs = append(xs, NewIntersection(t, s))
+	}
+	return xs
+}
@@ -0,0 +1,149 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRayMissesCylinder(t *testing.T) {
+	cyl := NewDefaultCylinder()
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+	}{
+		{NewPoint3(1, 0, 0), NewVec3(0, 1, 0)},
+		{NewPoint3(0, 0, 0), NewVec3(0, 1, 0)},
+		{NewPoint3(0, 0, -5), NewVec3(1, 1, 1)},
+	}
+
+	for _, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		xs := cyl.localIntersectWith(&r)
+
+		require.Len(t, xs, 0, "ray from %v", tc.origin)
+	}
+}
+
+func TestRayStrikesCylinder(t *testing.T) {
+	cyl := NewDefaultCylinder()
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+		t0, t1    float64
+	}{
+		{NewPoint3(1, 0, -5), NewVec3(0, 0, 1), 5, 5},
+		{NewPoint3(0, 0, -5), NewVec3(0, 0, 1), 4, 6},
+		{NewPoint3(0.5, 0, -5), NewVec3(0.1, 1, 1), 6.80798, 7.08872},
+	}
+
+	for _, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		xs := cyl.localIntersectWith(&r)
+
+		require.Len(t, xs, 2, "ray from %v", tc.origin)
+		require.InDelta(t, tc.t0, xs[0].time, EPSILON)
+		require.InDelta(t, tc.t1, xs[1].time, EPSILON)
+	}
+}
+
+func TestNormalOnCylinder(t *testing.T) {
+	cyl := NewDefaultCylinder()
+	testCases := []struct {
+		point  Point3
+		normal Vec3
+	}{
+		{NewPoint3(1, 0, 0), NewVec3(1, 0, 0)},
+		{NewPoint3(0, 5, -1), NewVec3(0, 0, -1)},
+		{NewPoint3(0, -2, 1), NewVec3(0, 0, 1)},
+		{NewPoint3(-1, 1, 0), NewVec3(-1, 0, 0)},
+	}
+
+	for _, tc := range testCases {
+		n := cyl.localNormalAt(tc.point, Intersection{})
+
+		require.True(t, n.Equal(tc.normal), "normal at %v is %v", tc.point, n)
+	}
+}
+
+func TestDefaultCylinderIsInfiniteAndOpen(t *testing.T) {
+	cyl := NewDefaultCylinder()
+
+	require.True(t, math.IsInf(cyl.minimum, -1))
+	require.True(t, math.IsInf(cyl.maximum, 1))
+	require.False(t, cyl.closed)
+}
+
+func TestIntersectingConstrainedCylinder(t *testing.T) {
+	cyl := NewDefaultCylinder()
+	cyl.Truncate(1, 2, false)
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+		count     int
+	}{
+		{NewPoint3(0, 1.5, 0), NewVec3(0.1, 1, 0), 0},
+		{NewPoint3(0, 3, -5), NewVec3(0, 0, 1), 0},
+		{NewPoint3(0, 0, -5), NewVec3(0, 0, 1), 0},
+		{NewPoint3(0, 2, -5), NewVec3(0, 0, 1), 0},
+		{NewPoint3(0, 1, -5), NewVec3(0, 0, 1), 0},
+		{NewPoint3(0, 1.5, -2), NewVec3(0, 0, 1), 2},
+	}
+
+	for i, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		xs := cyl.localIntersectWith(&r)
+
+		require.Len(t, xs, tc.count, "case %d", i+1)
+	}
+}
+
+func TestIntersectingCapsOfClosedCylinder(t *testing.T) {
+	cyl := NewDefaultCylinder()
+	cyl.Truncate(1, 2, true)
+	testCases := []struct {
+		origin    Point3
+		direction Vec3
+		count     int
+	}{
+		{NewPoint3(0, 3, 0), NewVec3(0, -1, 0), 2},
+		{NewPoint3(0, 3, -2), NewVec3(0, -1, 2), 2},
+		{NewPoint3(0, 4, -2), NewVec3(0, -1, 1), 2},
+		{NewPoint3(0, 0, -2), NewVec3(0, 1, 2), 2},
+		{NewPoint3(0, -1, -2), NewVec3(0, 1, 1), 2},
+	}
+
+	for i, tc := range testCases {
+		r := NewRay(tc.origin, tc.direction.Normalize())
+
+		xs := cyl.localIntersectWith(&r)
+
+		require.Len(t, xs, tc.count, "case %d", i+1)
+	}
+}
+
+func TestNormalOnCylinderCaps(t *testing.T) {
+	cyl := NewDefaultCylinder()
+	cyl.Truncate(1, 2, true)
+	testCases := []struct {
+		point  Point3
+		normal Vec3
+	}{
+		{NewPoint3(0, 1, 0), NewVec3(0, -1, 0)},
+		{NewPoint3(0.5, 1, 0), NewVec3(0, -1, 0)},
+		{NewPoint3(0, 1, 0.5), NewVec3(0, -1, 0)},
+		{NewPoint3(0, 2, 0), NewVec3(0, 1, 0)},
+		{NewPoint3(0.5, 2, 0), NewVec3(0, 1, 0)},
+		{NewPoint3(0, 2, 0.5), NewVec3(0, 1, 0)},
+	}
+
+	for _, tc := range testCases {
+		n := cyl.localNormalAt(tc.point, Intersection{})
+
+		require.True(t, n.Equal(tc.normal), "normal at %v is %v", tc.point, n)
+	}
+}
@@ -0,0 +1,93 @@
+package ray_tracer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Group is a shape consisting of other shapes (including other groups). Transformation
+// of the group is applied to all of its children on top of their own transformations.
+type Group struct {
+	shape
+	children []Shape
+	// nil until BuildBVH is called. Adding children drops it
+	bvh *bvh
+}
+
+func NewGroup(id string) *Group {
+	return &Group{shape: newShape(id, NewDefaultMaterial()), children: []Shape{}}
+}
+
+func NewDefaultGroup() *Group {
+	return NewGroup("group_id")
+}
+
+func (g *Group) AddChild(s Shape) {
+	s.setParent(g)
+	g.children = append(g.children, s)
+	g.dropBVHs()
+}
+
+// Bounds of the children changed, so both its own hierarchy and the ones it is part of are outdated
+func (g *Group) dropBVHs() {
+	g.bvh = nil
+	g.shape.dropBVHs()
+}
+
+func (g *Group) Children() []Shape {
+	return g.children
+}
+
+// Group itself is never drawn, so its material only matters as a way
+// to paint all the children at once
+func (g *Group) SetMaterial(m Material) {
+	g.material = m
+	for _, child := range g.children {
+		child.SetMaterial(m)
+	}
+}
+
+func (g *Group) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(g, r)
+}
+
+// Splits children into a bounding volume hierarchy (including children of the nested
+// groups), so rays don't have to be tested against every child. Adding children or
+// changing their transforms drops it, until it's built again
+func (g *Group) BuildBVH() BVHStats {
+	stats := BVHStats{}
+	for _, child := range g.children {
+		stats = stats.add(buildNestedBVHs(child))
+	}
+
+	g.bvh = buildBVH(g.children)
+	return stats.add(g.bvh.stats)
+}
+
+func (g *Group) Bounds() BoundingBox {
+	bounds := NewEmptyBoundingBox()
+	for _, child := range g.children {
+		bounds = bounds.Merge(parentSpaceBounds(child))
+	}
+	return bounds
+}
+
+func (g *Group) localIntersectWith(r *Ray) []Intersection {
+	xs := []Intersection{}
+	if g.bvh != nil {
+		xs = g.bvh.intersect(r)
+	} else {
+		for _, child := range g.children {
+			xs = append(xs, IntersectWith(child, r)...)
+		}
+	}
+
+	sort.Slice(xs, func(i, j int) bool { return xs[i].time < xs[j].time })
+	return xs
+}
+
+// Intersections never reference a group, but its children, so normal
+// is always calculated on a child
+func (g *Group) localNormalAt(point Point3, hit Intersection) Vec3 {
+	panic(fmt.Sprintf("Normal can't be calculated on a group %q!", g.id))
+}
@@ -0,0 +1,160 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatingNewGroup(t *testing.T) {
+	g := NewDefaultGroup()
+
+	transform := g.Transform()
+	require.True(t, transform.Equal(NewIdentityMatrix(4)))
+	require.Len(t, g.Children(), 0)
+}
+
+func TestShapeHasNoParentByDefault(t *testing.T) {
+	s := newTestShape()
+
+	require.Nil(t, s.Parent())
+}
+
+func TestAddingChildToGroup(t *testing.T) {
+	g := NewDefaultGroup()
+	s := newTestShape()
+
+	g.AddChild(s)
+
+	require.Len(t, g.Children(), 1)
+	require.Equal(t, s, g.Children()[0])
+	require.Equal(t, g, s.Parent())
+}
+
+func TestIntersectingRayWithEmptyGroup(t *testing.T) {
+	g := NewDefaultGroup()
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+
+	xs := g.localIntersectWith(&r)
+
+	require.Len(t, xs, 0)
+}
+
+func TestIntersectingRayWithNonemptyGroup(t *testing.T) {
+	g := NewDefaultGroup()
+	s1 := NewSphere("s1", NewDefaultMaterial())
+	s2 := NewSphere("s2", NewDefaultMaterial())
+	s2.SetTransform(NewTranslationMatrix(0, 0, -3))
+	s3 := NewSphere("s3", NewDefaultMaterial())
+	s3.SetTransform(NewTranslationMatrix(5, 0, 0))
+	g.AddChild(&s1)
+	g.AddChild(&s2)
+	g.AddChild(&s3)
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+
+	xs := g.localIntersectWith(&r)
+
+	require.Len(t, xs, 4)
+	require.Equal(t, &s2, xs[0].object)
+	require.Equal(t, &s2, xs[1].object)
+	require.Equal(t, &s1, xs[2].object)
+	require.Equal(t, &s1, xs[3].object)
+}
+
+func TestIntersectingTransformedGroup(t *testing.T) {
+	g := NewDefaultGroup()
+	g.SetTransform(NewScalingMatrix(2, 2, 2))
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(5, 0, 0))
+	g.AddChild(&s)
+	r := NewRay(NewPoint3(10, 0, -10), NewVec3(0, 0, 1))
+
+	xs := g.IntersectWith(&r)
+
+	require.Len(t, xs, 2)
+}
+
+func TestConvertingPointFromWorldToObjectSpace(t *testing.T) {
+	g1 := NewGroup("g1")
+	g1.SetTransform(NewRotationYMatrix(math.Pi / 2))
+	g2 := NewGroup("g2")
+	g2.SetTransform(NewScalingMatrix(2, 2, 2))
+	g1.AddChild(g2)
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(5, 0, 0))
+	g2.AddChild(&s)
+
+	p := worldToObject(&s, NewPoint3(-2, 0, -10), 0)
+
+	require.True(t, p.Equal(NewPoint3(0, 0, -1)), "point %v", p)
+}
+
+func TestConvertingNormalFromObjectToWorldSpace(t *testing.T) {
+	g1 := NewGroup("g1")
+	g1.SetTransform(NewRotationYMatrix(math.Pi / 2))
+	g2 := NewGroup("g2")
+	g2.SetTransform(NewScalingMatrix(1, 2, 3))
+	g1.AddChild(g2)
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(5, 0, 0))
+	g2.AddChild(&s)
+	v := math.Sqrt(3) / 3
+
+	n := normalToWorld(&s, NewVec3(v, v, v), 0)
+
+	require.True(t, n.Equal(NewVec3(0.28571, 0.42857, -0.85714)), "normal %v", n)
+}
+
+func TestFindingNormalOnChildObject(t *testing.T) {
+	g1 := NewGroup("g1")
+	g1.SetTransform(NewRotationYMatrix(math.Pi / 2))
+	g2 := NewGroup("g2")
+	g2.SetTransform(NewScalingMatrix(1, 2, 3))
+	g1.AddChild(g2)
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(5, 0, 0))
+	g2.AddChild(&s)
+
+	n := s.NormalAt(NewPoint3(1.7321, 1.1547, -5.5774))
+
+	require.True(t, n.Equal(NewVec3(0.28570, 0.42854, -0.85716)), "normal %v", n)
+}
+
+func TestNormalOnGroupPanics(t *testing.T) {
+	g := NewDefaultGroup()
+
+	require.Panics(t, func() { g.localNormalAt(NewPoint3(0, 0, 0), Intersection{}) })
+}
+
+func TestSettingGroupMaterialPaintsChildren(t *testing.T) {
+	g := NewDefaultGroup()
+	s := NewDefaultSphere()
+	g.AddChild(&s)
+	m := NewDefaultMaterial()
+	m.color = RED
+
+	g.SetMaterial(m)
+
+	require.Equal(t, RED, s.material.color)
+}
+
+func TestMovingGroupInWorldMovesAllChildren(t *testing.T) {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(-10, 10, -10), WHITE))
+	table := NewGroup("table")
+	leg := NewDefaultCube()
+	leg.SetTransform(NewScalingMatrix(0.1, 1, 0.1))
+	table.AddChild(&leg)
+	table.SetTransform(NewTranslationMatrix(0, 0, 10))
+	w.Add("table", table)
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+
+	xs := w.IntersectWith(&r)
+
+	require.Len(t, xs, 2)
+	require.InDelta(t, 9.9, xs[0].time, EPSILON)
+	require.Equal(t, &leg, xs[0].object)
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+	require.True(t, comps.objectNormalv.Equal(NewVec3(0, 0, -1)))
+}
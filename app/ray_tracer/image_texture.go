@@ -0,0 +1,203 @@
+package ray_tracer
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 8k x 8k, the canvas of it takes 1.5 GiB already
+const MAX_PPM_PIXELS = 1 << 26
+
+// Loads PPM (.ppm) or PNG (.png) image into a canvas, the format is chosen by the extension
+func LoadImage(filename string) (Canvas, error) {
+	var read func(r io.Reader) (Canvas, error)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ppm":
+		read = ReadPpm
+	case ".png":
+		read = ReadPng
+	default:
+		return Canvas{}, fmt.Errorf("%s: unsupported image format", filename)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return Canvas{}, err
+	}
+	defer f.Close()
+
+	canvas, err := read(f)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	return canvas, nil
+}
+
+// Reads the next whitespace separated token of the PPM header, skipping "#" comments
+func readPpmToken(r *bufio.Reader) (string, error) {
+	var token strings.Builder
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF && token.Len() > 0 {
+			return token.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case b == '#' && token.Len() == 0:
+			if _, err := r.ReadString('\n'); err != nil {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if token.Len() > 0 {
+				return token.String(), nil
+			}
+		default:
+			token.WriteByte(b)
+		}
+	}
+}
+
+func readPpmNumber(r *bufio.Reader, what string) (int, error) {
+	token, err := readPpmToken(r)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", what, err)
+	}
+	n, err := strconv.Atoi(token)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", what, token)
+	}
+	return n, nil
+}
+
+// Reads plain (P3) or binary (P6) PPM image. Colors are scaled according to the
+// maximum color value of the file
+func ReadPpm(reader io.Reader) (Canvas, error) {
+	r := bufio.NewReader(reader)
+
+	magic, err := readPpmToken(r)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("reading magic number: %w", err)
+	}
+	if magic != "P3" && magic != "P6" {
+		return Canvas{}, fmt.Errorf("unsupported magic number %q", magic)
+	}
+
+	width, err := readPpmNumber(r, "width")
+	if err != nil {
+		return Canvas{}, err
+	}
+	height, err := readPpmNumber(r, "height")
+	if err != nil {
+		return Canvas{}, err
+	}
+	// the header may claim anything, so the canvas isn't allocated for more than a sane image
+	if width > 0 && height > MAX_PPM_PIXELS/width {
+		return Canvas{}, fmt.Errorf("image %dx%d is bigger than %d pixels", width, height, MAX_PPM_PIXELS)
+	}
+	maxValue, err := readPpmNumber(r, "maximum color value")
+	if err != nil {
+		return Canvas{}, err
+	}
+	if maxValue == 0 || maxValue > 65535 {
+		return Canvas{}, fmt.Errorf("maximum color value %d is out of range [1, 65535]", maxValue)
+	}
+
+	// binary samples take 2 bytes, if they don't fit into one
+	readSample := func() (int, error) {
+		return readPpmNumber(r, "color value")
+	}
+	if magic == "P6" {
+		readSample = func() (int, error) {
+			hi, err := r.ReadByte()
+			if err != nil || maxValue < 256 {
+				return int(hi), err
+			}
+			lo, err := r.ReadByte()
+			return int(hi)<<8 | int(lo), err
+		}
+	}
+
+	canvas := NewCanvas(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			rgb := [3]float64{}
+			for i := range rgb {
+				sample, err := readSample()
+				if err != nil {
+					return Canvas{}, fmt.Errorf("pixel (%d, %d): %w", x, y, err)
+				}
+				if sample > maxValue {
+					return Canvas{}, fmt.Errorf("pixel (%d, %d): color value %d is bigger than %d", x, y, sample, maxValue)
+				}
+				rgb[i] = float64(sample) / float64(maxValue)
+			}
+			canvas.WritePixel(x, y, NewColor(rgb[0], rgb[1], rgb[2]))
+		}
+	}
+	return canvas, nil
+}
+
+func ReadPng(r io.Reader) (Canvas, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return Canvas{}, err
+	}
+	return CanvasFromImage(img), nil
+}
+
+func CanvasFromImage(img image.Image) Canvas {
+	bounds := img.Bounds()
+	canvas := NewCanvas(bounds.Dx(), bounds.Dy())
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			// components are 16 bit and alpha premultiplied, transparency is ignored
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			canvas.WritePixel(x, y, NewColor(float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff))
+		}
+	}
+	return canvas
+}
+
+// Texture made of an image. Pixels are blended with bilinear filtering, so close up
+// textures look smooth instead of blocky
+type UVImagePattern struct {
+	canvas Canvas
+}
+
+func NewUVImagePattern(canvas Canvas) *UVImagePattern {
+	if canvas.width == 0 || canvas.height == 0 {
+		panic("Image texture can't be empty!")
+	}
+	return &UVImagePattern{canvas: canvas}
+}
+
+func (p *UVImagePattern) uvColorAt(u, v float64) Color {
+	u = math.Max(0, math.Min(1, u))
+	v = math.Max(0, math.Min(1, v))
+
+	// v == 1 is the top row of the image
+	x := u * float64(p.canvas.width-1)
+	y := (1 - v) * float64(p.canvas.height-1)
+
+	x0, y0 := math.Floor(x), math.Floor(y)
+	x1, y1 := math.Min(x0+1, float64(p.canvas.width-1)), math.Min(y0+1, float64(p.canvas.height-1))
+	fx, fy := x-x0, y-y0
+
+	topLeft, topRight := p.canvas.PixelAt(int(x0), int(y0)), p.canvas.PixelAt(int(x1), int(y0))
+	bottomLeft, bottomRight := p.canvas.PixelAt(int(x0), int(y1)), p.canvas.PixelAt(int(x1), int(y1))
+
+	top := topLeft.Add(topRight.Sub(topLeft).MultScalar(fx))
+	bottom := bottomLeft.Add(bottomRight.Sub(bottomLeft).MultScalar(fx))
+	return top.Add(bottom.Sub(top).MultScalar(fy))
+}
@@ -0,0 +1,211 @@
+package ray_tracer
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingPpmWithWrongMagicNumber(t *testing.T) {
+	ppm := "P32\n1 1\n255\n0 0 0\n"
+
+	_, err := ReadPpm(strings.NewReader(ppm))
+
+	require.ErrorContains(t, err, "magic number")
+}
+
+func TestReadingPpmReturnsCanvasOfTheRightSize(t *testing.T) {
+	ppm := "P3\n10 2\n255\n" + strings.Repeat("0 0 0 ", 20)
+
+	c, err := ReadPpm(strings.NewReader(ppm))
+
+	require.NoError(t, err)
+	require.Equal(t, 10, c.width)
+	require.Equal(t, 2, c.height)
+}
+
+func TestReadingPixelDataFromPpm(t *testing.T) {
+	ppm := `P3
+4 3
+255
+255 127 0  0 127 255  127 255 0  255 255 255
+0 0 0  255 0 0  0 255 0  0 0 255
+255 255 0  0 255 255  255 0 255  127 127 127
+`
+
+	c, err := ReadPpm(strings.NewReader(ppm))
+
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		x, y   int
+		expect Color
+	}{
+		{0, 0, NewColor(1, 0.49804, 0)},
+		{1, 0, NewColor(0, 0.49804, 1)},
+		{2, 0, NewColor(0.49804, 1, 0)},
+		{3, 0, NewColor(1, 1, 1)},
+		{0, 1, NewColor(0, 0, 0)},
+		{1, 1, NewColor(1, 0, 0)},
+		{2, 1, NewColor(0, 1, 0)},
+		{3, 1, NewColor(0, 0, 1)},
+		{0, 2, NewColor(1, 1, 0)},
+		{1, 2, NewColor(0, 1, 1)},
+		{2, 2, NewColor(1, 0, 1)},
+		{3, 2, NewColor(0.49804, 0.49804, 0.49804)},
+	} {
+		require.True(t, c.PixelAt(tc.x, tc.y).Equal(tc.expect), "pixel (%d, %d)", tc.x, tc.y)
+	}
+}
+
+func TestReadingPpmIgnoresCommentLines(t *testing.T) {
+	ppm := "P3\n# this is a comment\n2 1\n# this, too\n255\n# another comment\n255 255 255\n# oh, no, comments in the pixel data!\n255 0 255\n"
+
+	c, err := ReadPpm(strings.NewReader(ppm))
+
+	require.NoError(t, err)
+	require.True(t, c.PixelAt(0, 0).Equal(WHITE))
+	require.True(t, c.PixelAt(1, 0).Equal(NewColor(1, 0, 1)))
+}
+
+func TestReadingPpmAllowsRgbTriplesToSpanLines(t *testing.T) {
+	ppm := "P3\n1 1\n255\n51\n153\n\n204\n"
+
+	c, err := ReadPpm(strings.NewReader(ppm))
+
+	require.NoError(t, err)
+	require.True(t, c.PixelAt(0, 0).Equal(NewColor(0.2, 0.6, 0.8)))
+}
+
+func TestReadingPpmRespectsTheScaleSetting(t *testing.T) {
+	ppm := "P3\n2 2\n100\n100 100 100  50 50 50\n75 50 25  0 0 0\n"
+
+	c, err := ReadPpm(strings.NewReader(ppm))
+
+	require.NoError(t, err)
+	require.True(t, c.PixelAt(0, 1).Equal(NewColor(0.75, 0.5, 0.25)))
+}
+
+func TestReadingBinaryPpm(t *testing.T) {
+	ppm := append([]byte("P6\n2 1\n255\n"), 255, 0, 51, 0, 255, 0)
+
+	c, err := ReadPpm(bytes.NewReader(ppm))
+
+	require.NoError(t, err)
+	require.True(t, c.PixelAt(0, 0).Equal(NewColor(1, 0, 0.2)))
+	require.True(t, c.PixelAt(1, 0).Equal(GREEN))
+}
+
+func TestReadingTruncatedPpmFails(t *testing.T) {
+	_, err := ReadPpm(strings.NewReader("P3\n2 1\n255\n255 255 255 0\n"))
+	require.Error(t, err)
+
+	_, err = ReadPpm(strings.NewReader("P3\n1 1\n255\n256 0 0\n"))
+	require.ErrorContains(t, err, "bigger than")
+}
+
+func TestReadingHugePpmFailsBeforeAllocatingCanvas(t *testing.T) {
+	_, err := ReadPpm(strings.NewReader("P6\n1000000 1000000\n255\n"))
+
+	require.EqualError(t, err, "image 1000000x1000000 is bigger than 67108864 pixels")
+}
+
+func TestWrittenPpmCanBeReadBack(t *testing.T) {
+	c := NewCanvas(3, 2)
+	c.WritePixel(0, 0, RED)
+	c.WritePixel(2, 1, NewColor(0.2, 0.4, 0.6))
+
+	res, err := ReadPpm(strings.NewReader(c.PpmData()))
+
+	require.NoError(t, err)
+	require.True(t, res.PixelAt(0, 0).Equal(RED))
+	require.InDelta(t, 0.4, res.PixelAt(2, 1).g, 1./MAX_COLORS)
+}
+
+func newTestPng(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{0, 0, 255, 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestReadingPng(t *testing.T) {
+	c, err := ReadPng(bytes.NewReader(newTestPng(t)))
+
+	require.NoError(t, err)
+	require.Equal(t, 2, c.width)
+	require.Equal(t, 1, c.height)
+	require.True(t, c.PixelAt(0, 0).Equal(RED))
+	require.True(t, c.PixelAt(1, 0).Equal(BLUE))
+}
+
+func TestLoadingImageChoosesFormatByExtension(t *testing.T) {
+	dir := t.TempDir()
+	pngFile := filepath.Join(dir, "texture.PNG")
+	require.NoError(t, os.WriteFile(pngFile, newTestPng(t), 0644))
+	ppmFile := filepath.Join(dir, "texture.ppm")
+	require.NoError(t, os.WriteFile(ppmFile, []byte("P3\n1 1\n255\n0 255 0\n"), 0644))
+
+	c, err := LoadImage(pngFile)
+	require.NoError(t, err)
+	require.True(t, c.PixelAt(1, 0).Equal(BLUE))
+
+	c, err = LoadImage(ppmFile)
+	require.NoError(t, err)
+	require.True(t, c.PixelAt(0, 0).Equal(GREEN))
+
+	_, err = LoadImage(filepath.Join(dir, "texture.jpg"))
+	require.ErrorContains(t, err, "unsupported image format")
+}
+
+func TestImageTextureCorners(t *testing.T) {
+	c := NewCanvas(2, 2)
+	c.WritePixel(0, 0, RED)
+	c.WritePixel(1, 0, GREEN)
+	c.WritePixel(0, 1, BLUE)
+	c.WritePixel(1, 1, WHITE)
+	p := NewUVImagePattern(c)
+
+	// the top row of the image is at v == 1
+	require.True(t, p.uvColorAt(0, 1).Equal(RED))
+	require.True(t, p.uvColorAt(1, 1).Equal(GREEN))
+	require.True(t, p.uvColorAt(0, 0).Equal(BLUE))
+	require.True(t, p.uvColorAt(1, 0).Equal(WHITE))
+}
+
+func TestImageTextureIsFilteredBilinearly(t *testing.T) {
+	c := NewCanvas(2, 2)
+	c.WritePixel(0, 0, RED)
+	c.WritePixel(1, 0, GREEN)
+	c.WritePixel(0, 1, BLUE)
+	c.WritePixel(1, 1, WHITE)
+	p := NewUVImagePattern(c)
+
+	require.True(t, p.uvColorAt(0.5, 1).Equal(NewColor(0.5, 0.5, 0)))
+	require.True(t, p.uvColorAt(0, 0.5).Equal(NewColor(0.5, 0, 0.5)))
+	require.True(t, p.uvColorAt(0.5, 0.5).Equal(NewColor(0.5, 0.5, 0.5)))
+	require.True(t, p.uvColorAt(0.25, 0.75).Equal(NewColor(0.625, 0.25, 0.25)))
+}
+
+func TestImageTextureOnSphere(t *testing.T) {
+	c := NewCanvas(3, 3)
+	c.Fill(BLUE)
+	c.WritePixel(1, 0, WHITE)
+	c.WritePixel(1, 2, RED)
+	p := NewTextureMapPattern(NewUVImagePattern(c), SphericalMap)
+	s := NewDefaultSphere()
+	s.material.pattern = p
+
+	require.True(t, PatternAtShape(p, &s, NewPoint3(0, 1, 0)).Equal(WHITE))
+	require.True(t, PatternAtShape(p, &s, NewPoint3(0, -1, 0)).Equal(RED))
+	require.Panics(t, func() { NewUVImagePattern(NewCanvas(0, 0)) })
+}
@@ -4,11 +4,18 @@ import "sort"
 
 type Intersection struct {
 	time   float64
-	object *Sphere
+	object Shape
+	// Barycentric coordinates of the intersection, used only by triangles
+	u float64
+	v float64
 }
 
-func NewIntersection(t float64, object *Sphere) Intersection {
-	return Intersection{t, object}
+func NewIntersection(t float64, object Shape) Intersection {
+	return Intersection{time: t, object: object}
+}
+
+func NewIntersectionWithUV(t float64, object Shape, u, v float64) Intersection {
+	return Intersection{time: t, object: object, u: u, v: v}
 }
 
 func Hit(intersections []Intersection) (Intersection, bool) {
@@ -26,32 +33,81 @@ func Hit(intersections []Intersection) (Intersection, bool) {
 
 type IntersectionComputations struct {
 	intersectionTime   float64
-	intersectionObject *Sphere
-	intersectionPoint  Tuple
-	overPoint          Tuple
-	eyev               Tuple
-	objectNormalv      Tuple
+	intersectionObject Shape
+	intersectionPoint  Point3
+	overPoint          Point3
+	underPoint         Point3
+	eyev               Vec3
+	objectNormalv      Vec3
+	reflectv           Vec3
 	insideHit          bool
+	// moment the ray was cast at, secondary rays are cast at the same moment
+	rayTime float64
+	// refractive indices of the materials the ray is leaving and entering
+	n1 float64
+	n2 float64
 }
 
-func PrepareIntersectionComputations(i Intersection, r Ray) IntersectionComputations {
+// xs are all the intersections of the ray, they are needed to find out which objects
+// contain the hit and so the refractive indices on both sides of it. If xs is empty,
+// the ray is assumed to go through vacuum
+func PrepareIntersectionComputations(i Intersection, r Ray, xs []Intersection) IntersectionComputations {
 	comps := IntersectionComputations{
 		intersectionTime:   i.time,
 		intersectionObject: i.object,
 		insideHit:          false,
+		rayTime:            r.time,
 	}
 	comps.intersectionPoint = r.CalcPosition(i.time)
 	comps.eyev = r.direction.Mul(-1)
-	comps.objectNormalv = i.object.NormalAt(comps.intersectionPoint)
+	comps.objectNormalv = normalAtTime(i.object, comps.intersectionPoint, i, r.time)
 
 	if comps.eyev.Dot(comps.objectNormalv) < 0 {
 		comps.insideHit = true
 		comps.objectNormalv = comps.objectNormalv.Mul(-1)
 	}
+	comps.reflectv = r.direction.ReflectAround(comps.objectNormalv)
 
 	// A point, very close to the intersection point, but adjusted a bit into the
 	// direction of a normal. Used to fight the "acne effect", while testing for shadowing
 	comps.overPoint = comps.intersectionPoint.Add(comps.objectNormalv.Mul(EPSILON))
+	// Same, but under the surface. Refracted rays start here
+	comps.underPoint = comps.intersectionPoint.SubVec(comps.objectNormalv.Mul(EPSILON))
+	comps.n1, comps.n2 = refractiveIndices(i, xs)
 
 	return comps
 }
+
+// Goes through the intersections keeping the list of objects the ray is currently
+// inside of. The last one entered before the hit is the one the ray leaves (n1)
+// and the last one after the hit is the one the ray enters (n2)
+func refractiveIndices(hit Intersection, xs []Intersection) (n1, n2 float64) {
+	n1, n2 = VACUUM_REFRACTIVE_INDEX, VACUUM_REFRACTIVE_INDEX
+	containers := []Shape{}
+
+	for _, i := range xs {
+		if i == hit && len(containers) > 0 {
+			n1 = containers[len(containers)-1].Material().refractiveIndex
+		}
+
+		found := false
+		for j, c := range containers {
+			if c == i.object {
+				containers = append(containers[:j], containers[j+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			containers = append(containers, i.object)
+		}
+
+		if i == hit {
+			if len(containers) > 0 {
+				n2 = containers[len(containers)-1].Material().refractiveIndex
+			}
+			break
+		}
+	}
+	return n1, n2
+}
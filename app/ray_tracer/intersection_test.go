@@ -1,6 +1,7 @@
 package ray_tracer
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,19 +13,19 @@ func TestIntersectionEncapsulatesTimeAndObject(t *testing.T) {
 	i := NewIntersection(3.5, &s)
 
 	require.EqualValues(t, 3.5, i.time)
-	require.EqualValues(t, s, *i.object)
+	require.EqualValues(t, &s, i.object)
 }
 
 func TestIntersectSetsTheObjectOnTheIntersection(t *testing.T) {
-	origin, direction := NewPoint(0, 0, -5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 0, -5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 
 	xs := s.IntersectWith(&r)
 
 	require.EqualValues(t, 2, len(xs))
-	require.EqualValues(t, s, *xs[0].object)
-	require.EqualValues(t, s, *xs[1].object)
+	require.EqualValues(t, &s, xs[0].object)
+	require.EqualValues(t, &s, xs[1].object)
 }
 
 func TestHitWithAllIntersectionsWithPositiveT(t *testing.T) {
@@ -77,52 +78,107 @@ func TestHitIsAlwaysTheLowestNonnegativeIntersection(t *testing.T) {
 }
 
 func TestPrecomputingTheStateOfAnIntersection(t *testing.T) {
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
 	s := NewDefaultSphere()
 	i := NewIntersection(4, &s)
 
-	comps := PrepareIntersectionComputations(i, r)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
 
 	require.EqualValues(t, comps.intersectionTime, i.time)
 	require.EqualValues(t, comps.intersectionObject, i.object)
-	require.True(t, comps.intersectionPoint.Equal(NewPoint(0, 0, -1)))
-	require.True(t, comps.eyev.Equal(NewVector(0, 0, -1)))
-	require.True(t, comps.objectNormalv.Equal(NewVector(0, 0, -1)))
+	require.True(t, comps.intersectionPoint.Equal(NewPoint3(0, 0, -1)))
+	require.True(t, comps.eyev.Equal(NewVec3(0, 0, -1)))
+	require.True(t, comps.objectNormalv.Equal(NewVec3(0, 0, -1)))
 }
 
 func TestTheHitWithOutsideIntersection(t *testing.T) {
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
 	s := NewDefaultSphere()
 	i := NewIntersection(4, &s)
 
-	comps := PrepareIntersectionComputations(i, r)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
 
 	require.False(t, comps.insideHit)
 }
 
 func TestTheHitWithInsideIntersection(t *testing.T) {
-	r := NewRay(NewPoint(0, 0, 0), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
 	s := NewDefaultSphere()
 	i := NewIntersection(1, &s)
 
-	comps := PrepareIntersectionComputations(i, r)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
 
-	require.True(t, comps.intersectionPoint.Equal(NewPoint(0, 0, 1)))
-	require.True(t, comps.eyev.Equal(NewVector(0, 0, -1)))
+	require.True(t, comps.intersectionPoint.Equal(NewPoint3(0, 0, 1)))
+	require.True(t, comps.eyev.Equal(NewVec3(0, 0, -1)))
 	require.True(t, comps.insideHit)
 	// normal would have been (0, 0, 1), but is inverted!
-	require.True(t, comps.objectNormalv.Equal(NewVector(0, 0, -1)))
+	require.True(t, comps.objectNormalv.Equal(NewVec3(0, 0, -1)))
 }
 
 // Test, that "acne effect" can be successfully overcome
 func TestTheHitShouldOffsetThePoint(t *testing.T) {
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
 	s := NewDefaultSphere()
 	s.SetTransform(NewTranslationMatrix(0, 0, 1))
 	i := NewIntersection(5, &s)
 
-	comps := PrepareIntersectionComputations(i, r)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
 
 	require.Less(t, comps.overPoint.z, -EPSILON/2)
 	require.Greater(t, comps.intersectionPoint.z, comps.overPoint.z)
 }
+
+func TestPrecomputingTheReflectionVector(t *testing.T) {
+	p := NewDefaultPlane()
+	r := NewRay(NewPoint3(0, 1, -1), NewVec3(0, -COS45, COS45))
+	i := NewIntersection(math.Sqrt2, &p)
+
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	require.True(t, comps.reflectv.Equal(NewVec3(0, COS45, COS45)))
+}
+
+func newGlassSphere(id string) Sphere {
+	m := NewDefaultMaterial()
+	m.transparency = 1
+	m.refractiveIndex = 1.5
+	return NewSphere(id, m)
+}
+
+func TestFindingRefractiveIndicesAtVariousIntersections(t *testing.T) {
+	a := newGlassSphere("A")
+	a.SetTransform(NewScalingMatrix(2, 2, 2))
+	b := newGlassSphere("B")
+	b.SetTransform(NewTranslationMatrix(0, 0, -0.25))
+	b.material.refractiveIndex = 2
+	c := newGlassSphere("C")
+	c.SetTransform(NewTranslationMatrix(0, 0, 0.25))
+	c.material.refractiveIndex = 2.5
+	r := NewRay(NewPoint3(0, 0, -4), NewVec3(0, 0, 1))
+	xs := []Intersection{
+		NewIntersection(2, &a), NewIntersection(2.75, &b), NewIntersection(3.25, &c),
+		NewIntersection(4.75, &b), NewIntersection(5.25, &c), NewIntersection(6, &a),
+	}
+	expected := []struct{ n1, n2 float64 }{
+		{1.0, 1.5}, {1.5, 2.0}, {2.0, 2.5}, {2.5, 2.5}, {2.5, 1.5}, {1.5, 1.0},
+	}
+
+	for i, e := range expected {
+		comps := PrepareIntersectionComputations(xs[i], r, xs)
+
+		require.EqualValues(t, e.n1, comps.n1, "intersection %d", i)
+		require.EqualValues(t, e.n2, comps.n2, "intersection %d", i)
+	}
+}
+
+func TestTheUnderPointIsOffsetBelowTheSurface(t *testing.T) {
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	s := newGlassSphere("s")
+	s.SetTransform(NewTranslationMatrix(0, 0, 1))
+	i := NewIntersection(5, &s)
+
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	require.Greater(t, comps.underPoint.z, EPSILON/2)
+	require.Less(t, comps.intersectionPoint.z, comps.underPoint.z)
+}
@@ -0,0 +1,125 @@
+package ray_tracer
+
+import "math"
+
+// Light is anything that illuminates the world. Every light is represented by
+// one or more samples, which are checked for shadows and lit the surface separately
+type Light interface {
+	Intensity() Color
+	// Samples of the light as seen from the point
+	samplesAt(point Point3) []lightSample
+}
+
+// Light reaching only a part of the scene, e.g. a spot light. Its ambient term is
+// limited to that part too
+type partialLight interface {
+	Light
+	// Fraction of the intensity reaching the point
+	falloff(point Point3) float64
+}
+
+// Light coming to the point from a single direction
+type lightSample struct {
+	// normalized vector from the point to the light
+	direction Vec3
+	// distance from the point to the light, shadows casted by the objects farther
+	// than that are ignored
+	distance  float64
+	intensity Color
+}
+
+// Samples the light from the position
+func newLightSample(point, position Point3, intensity Color) lightSample {
+	pointToLight := position.Sub(point)
+	distance := pointToLight.Magnitude()
+	return lightSample{direction: pointToLight.Normalize(), distance: distance, intensity: intensity}
+}
+
+type PointLight struct {
+	position  Point3
+	intensity Color
+}
+
+func NewPointLight(position Point3, intensity Color) PointLight {
+	return PointLight{position: position, intensity: intensity}
+}
+
+func (l PointLight) Intensity() Color {
+	return l.intensity
+}
+
+func (l PointLight) samplesAt(point Point3) []lightSample {
+	return []lightSample{newLightSample(point, l.position, l.intensity)}
+}
+
+// Point light shining only inside a cone. Inside the inner cone the light has full
+// intensity, between the inner and the outer cones it smoothly fades out
+type SpotLight struct {
+	position Point3
+	// axis of the cone, normalized
+	direction Vec3
+	// cosines of the half-angles of the cones
+	cosInner  float64
+	cosOuter  float64
+	intensity Color
+}
+
+// innerAngle and outerAngle are the angles (in radians) between the axis and the
+// sides of the cones
+func NewSpotLight(position Point3, direction Vec3, innerAngle, outerAngle float64, intensity Color) SpotLight {
+	if innerAngle < 0 || innerAngle > outerAngle || outerAngle > math.Pi {
+		panic("Spot light angles must satisfy 0 <= inner <= outer <= pi!")
+	}
+
+	return SpotLight{
+		position:  position,
+		direction: direction.Normalize(),
+		cosInner:  math.Cos(innerAngle),
+		cosOuter:  math.Cos(outerAngle),
+		intensity: intensity,
+	}
+}
+
+func (l SpotLight) Intensity() Color {
+	return l.intensity
+}
+
+// Fraction of the intensity reaching the point: 1 inside the inner cone, 0 outside
+// of the outer one and smoothstep in between
+func (l SpotLight) falloff(point Point3) float64 {
+	cos := point.Sub(l.position).Normalize().Dot(l.direction)
+	if cos >= l.cosInner {
+		return 1
+	}
+	if cos <= l.cosOuter {
+		return 0
+	}
+
+	t := (cos - l.cosOuter) / (l.cosInner - l.cosOuter)
+	return t * t * (3 - 2*t)
+}
+
+func (l SpotLight) samplesAt(point Point3) []lightSample {
+	intensity := l.intensity.MultScalar(l.falloff(point))
+	return []lightSample{newLightSample(point, l.position, intensity)}
+}
+
+// Light so far away (like the sun), that all its rays are parallel
+type DirectionalLight struct {
+	// direction the light travels in, normalized
+	direction Vec3
+	intensity Color
+}
+
+func NewDirectionalLight(direction Vec3, intensity Color) DirectionalLight {
+	return DirectionalLight{direction: direction.Normalize(), intensity: intensity}
+}
+
+func (l DirectionalLight) Intensity() Color {
+	return l.intensity
+}
+
+// Every object on the way to the light casts a shadow, no matter how far it is
+func (l DirectionalLight) samplesAt(point Point3) []lightSample {
+	return []lightSample{{direction: l.direction.Negate(), distance: math.Inf(1), intensity: l.intensity}}
+}
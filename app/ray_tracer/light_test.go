@@ -0,0 +1,118 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointLightHasSingleSample(t *testing.T) {
+	l := NewPointLight(NewPoint3(0, 10, 0), WHITE)
+
+	samples := l.samplesAt(NewPoint3(0, 0, 0))
+
+	require.Equal(t, []lightSample{{direction: NewVec3(0, 1, 0), distance: 10, intensity: WHITE}}, samples)
+}
+
+func TestSpotLightFallsOffBetweenInnerAndOuterCones(t *testing.T) {
+	l := NewSpotLight(NewPoint3(0, 10, 0), NewVec3(0, -2, 0), math.Pi/6, math.Pi/3, WHITE)
+
+	require.Equal(t, WHITE, l.Intensity())
+	require.EqualValues(t, 1, l.falloff(NewPoint3(0, 0, 0)))
+	// 45 degrees is halfway between the cones in terms of the angle, but not the cosine
+	require.InDelta(t, 0.59817, l.falloff(NewPoint3(10, 0, 0)), EPSILON)
+	require.EqualValues(t, 0, l.falloff(NewPoint3(100, 0, 0)))
+	require.EqualValues(t, 0, l.falloff(NewPoint3(0, 20, 0)))
+}
+
+func TestSpotLightSampleIsDimmedByFalloff(t *testing.T) {
+	l := NewSpotLight(NewPoint3(0, 10, 0), NewVec3(0, -1, 0), math.Pi/6, math.Pi/3, WHITE)
+	point := NewPoint3(10, 0, 0)
+
+	samples := l.samplesAt(point)
+
+	require.Len(t, samples, 1)
+	require.True(t, samples[0].direction.Equal(NewVec3(-COS45, COS45, 0)))
+	require.InDelta(t, 10*math.Sqrt2, samples[0].distance, EPSILON)
+	require.True(t, samples[0].intensity.Equal(WHITE.MultScalar(l.falloff(point))))
+}
+
+func TestSpotLightAnglesAreValidated(t *testing.T) {
+	require.Panics(t, func() { NewSpotLight(NewPoint3(0, 0, 0), NewVec3(0, -1, 0), math.Pi/3, math.Pi/6, WHITE) })
+	require.Panics(t, func() { NewSpotLight(NewPoint3(0, 0, 0), NewVec3(0, -1, 0), -1, math.Pi/6, WHITE) })
+	require.NotPanics(t, func() { NewSpotLight(NewPoint3(0, 0, 0), NewVec3(0, -1, 0), 0, 0, WHITE) })
+}
+
+func TestLightingOutsideOfSpotLightIsBlack(t *testing.T) {
+	m := NewDefaultMaterial()
+	l := NewSpotLight(NewPoint3(0, 0, -10), NewVec3(0, 1, 0), math.Pi/6, math.Pi/4, WHITE)
+	eye, normal := NewVec3(0, 0, -1), NewVec3(0, 0, -1)
+
+	res := CalcLighting(m, nil, l, NewPoint3(0, 0, 0), eye, normal, false)
+
+	require.Equal(t, BLACK, res)
+}
+
+func TestAmbientOfSpotLightFallsOffLikeTheRestOfIt(t *testing.T) {
+	m := NewDefaultMaterial()
+	l := NewSpotLight(NewPoint3(0, 10, 0), NewVec3(0, -1, 0), math.Pi/6, math.Pi/3, WHITE)
+	point := NewPoint3(10, 0, 0)
+	eye, normal := NewVec3(0, 0, -1), NewVec3(0, 0, -1)
+
+	res := CalcLighting(m, nil, l, point, eye, normal, true)
+
+	require.True(t, res.Equal(NewColor(0.1, 0.1, 0.1).MultScalar(l.falloff(point))), res)
+}
+
+func TestLightingInsideOfSpotLightIsLikeWithPointLight(t *testing.T) {
+	m := NewDefaultMaterial()
+	spot := NewSpotLight(NewPoint3(0, 0, -10), NewVec3(0, 0, 1), math.Pi/6, math.Pi/4, WHITE)
+	point := NewPointLight(NewPoint3(0, 0, -10), WHITE)
+	eye, normal := NewVec3(0, 0, -1), NewVec3(0, 0, -1)
+
+	res := CalcLighting(m, nil, spot, NewPoint3(0, 0, 0), eye, normal, false)
+
+	require.True(t, res.Equal(CalcLighting(m, nil, point, NewPoint3(0, 0, 0), eye, normal, false)))
+}
+
+func TestDirectionalLightComesFromInfinitelyFar(t *testing.T) {
+	l := NewDirectionalLight(NewVec3(0, -3, 0), NewColor(0.5, 0.5, 0.5))
+
+	for _, point := range []Point3{NewPoint3(0, 0, 0), NewPoint3(100, -5, 3)} {
+		samples := l.samplesAt(point)
+
+		require.Len(t, samples, 1)
+		require.True(t, samples[0].direction.Equal(NewVec3(0, 1, 0)))
+		require.True(t, math.IsInf(samples[0].distance, 1))
+	}
+}
+
+func TestEveryObjectOnTheWayCastsShadowOfDirectionalLight(t *testing.T) {
+	w := NewWorld()
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(0, 1000, 0))
+	w.Add("far_away", &s)
+	l := NewDirectionalLight(NewVec3(0, -1, 0), WHITE)
+	w.AddLight("sun", l)
+
+	require.True(t, IsShadowed(w, l, NewPoint3(0, 0, 0)))
+	require.EqualValues(t, 0, LightIntensityAt(w, l, NewPoint3(0, 0, 0)))
+	require.False(t, IsShadowed(w, l, NewPoint3(2, 0, 0)))
+	require.EqualValues(t, 1, LightIntensityAt(w, l, NewPoint3(2, 0, 0)))
+}
+
+func TestShadeHitWithDirectionalLight(t *testing.T) {
+	w := NewWorld()
+	floor := NewDefaultPlane()
+	w.Add("floor", &floor)
+	w.AddLight("sun", NewDirectionalLight(NewVec3(0, -1, 0), WHITE))
+	r := NewRay(NewPoint3(0, 1, 0), NewVec3(0, -1, 0))
+	i := NewIntersection(1, &floor)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	// ambient + diffuse + specular, the light is exactly behind the eye
+	require.True(t, res.Equal(NewColor(1.9, 1.9, 1.9)), res)
+}
@@ -4,72 +4,191 @@ import (
 	"math"
 )
 
-type PointLight struct {
-	position  Tuple
-	intensity Color
-}
-
-func NewPointLight(position Tuple, intensity Color) PointLight {
-	return PointLight{position: position, intensity: intensity}
-}
-
-func CalcLighting(material Material, light PointLight, position, eyeV, normalV Tuple, isInShadow bool) Color {
-	if !position.IsPoint() {
-		panic("Position must be a point!")
+// object is the shape being lit, it's needed to put the material's pattern on it.
+// Can be nil, if the material has no pattern
+func CalcLighting(material Material, object Shape, light Light, position Point3, eyeV, normalV Vec3, isInShadow bool) Color {
+	lightIntensity := 1.
+	if isInShadow {
+		lightIntensity = 0
 	}
+	return calcPartialLighting(material, object, light, position, eyeV, normalV, lightIntensity, 0)
+}
 
-	if !eyeV.IsVector() || !normalV.IsVector() {
-		panic("Eye and Normal must be vectors!")
+// Same as CalcLighting, but the point may be partially shadowed. lightIntensity is
+// the fraction of the light reaching the point: 0 in full shadow, 1 if fully lit.
+// time is the moment of the ray, it's needed to put the pattern on a moving object
+func calcPartialLighting(material Material, object Shape, light Light, position Point3, eyeV, normalV Vec3,
+	lightIntensity float64, time float64) Color {
+	color := surfaceColor(material, object, position, time)
+	ambient := color.MultHadamar(light.Intensity()).MultScalar(material.ambient)
+	if partial, ok := light.(partialLight); ok {
+		ambient = ambient.MultScalar(partial.falloff(position))
 	}
-
-	effectiveColor := material.color.MultHadamar(light.intensity)
-	ligthV := light.position.Sub(position).Normalize()
-	ambient := effectiveColor.MultScalar(material.ambient)
-
-	if isInShadow {
+	if lightIntensity == 0 {
 		return ambient
 	}
 
-	// negative dot product means the light is on the other side of the surface
-	// and should not contribute to the final lighting
-	lightDotNormal := ligthV.Dot(normalV)
-	diffuse, specular := BLACK, BLACK
-	if lightDotNormal >= 0 {
-		diffuse = effectiveColor.MultScalar(material.diffuse).MultScalar(lightDotNormal)
+	// diffuse and specular parts are averaged over all the samples of the light
+	samples := light.samplesAt(position)
+	sum := BLACK
+	for _, sample := range samples {
+		// negative dot product means the light is on the other side of the surface
+		// and should not contribute to the final lighting
+		lightDotNormal := sample.direction.Dot(normalV)
+		if lightDotNormal < 0 {
+			continue
+		}
+
+		effectiveColor := color.MultHadamar(sample.intensity)
+		diffuse := effectiveColor.MultScalar(material.diffuse).MultScalar(lightDotNormal)
+		sum = sum.Add(diffuse)
 
-		reflectV := ligthV.Mul(-1).ReflectAround(normalV)
+		reflectV := sample.direction.Mul(-1).ReflectAround(normalV)
 		// the same for reflected light. If negative -> reflected light doesn't contribute
 		// final intensity (specular == Black)
 		reflectDotEye := reflectV.Dot(eyeV)
 		if reflectDotEye > 0 {
 			factor := math.Pow(reflectDotEye, material.shininess)
-			specular = light.intensity.MultScalar(material.specular).MultScalar(factor)
+			specular := sample.intensity.MultScalar(material.specular).MultScalar(factor)
+			sum = sum.Add(specular)
 		}
 	}
 
-	return ambient.Add(diffuse).Add(specular)
+	return ambient.Add(sum.MultScalar(lightIntensity / float64(len(samples))))
+}
+
+// Color of the material at the point, the pattern is taken into account
+func surfaceColor(material Material, object Shape, position Point3, time float64) Color {
+	if material.pattern != nil {
+		return patternAtShapeAtTime(material.pattern, object, position, time)
+	}
+	return material.color
+}
+
+// remaining is the number of reflections left to follow from this hit
+func ShadeHit(world *World, comps *IntersectionComputations, remaining int) Color {
+	material := comps.intersectionObject.Material()
+
+	// every light is checked for shadows and contributes separately
+	surface := BLACK
+	for _, light := range world.Lights() {
+		lightIntensity := lightIntensityAtTime(world, light, comps.overPoint, comps.rayTime)
+		surface = surface.Add(calcPartialLighting(material, comps.intersectionObject, light, comps.overPoint,
+			comps.eyev, comps.objectNormalv, lightIntensity, comps.rayTime))
+	}
+	reflected := ReflectedColor(world, comps, remaining)
+	refracted := RefractedColor(world, comps, remaining)
+
+	if material.reflective > 0 && material.transparency > 0 {
+		reflectance := Schlick(comps)
+		return surface.Add(reflected.MultScalar(reflectance)).Add(refracted.MultScalar(1 - reflectance))
+	}
+	return surface.Add(reflected).Add(refracted)
 }
 
-func ShadeHit(world World, comps *IntersectionComputations) Color {
-	// TODO: Add support of multiple lights``
+// Color seen in the reflective surface. Returns BLACK for nonreflective materials or
+// when there are no reflections left, so mirrors facing each other don't recurse forever
+func ReflectedColor(world *World, comps *IntersectionComputations, remaining int) Color {
+	reflective := comps.intersectionObject.Material().reflective
+	if remaining <= 0 || reflective == 0 {
+		return BLACK
+	}
 
-	isShadowed := IsShadowed(world, comps.overPoint)
-	return CalcLighting(comps.intersectionObject.material, world.Light(), comps.overPoint,
-		comps.eyev, comps.objectNormalv, isShadowed)
+	reflectRay := NewRayAtTime(comps.overPoint, comps.reflectv, comps.rayTime)
+	color := world.ColorAtIntersection(reflectRay, remaining-1)
+	return color.MultScalar(reflective)
 }
 
-// Checks if theres smth between point and the light source.
-// What to do if there are 1+ light sources?
-func IsShadowed(world World, point Tuple) bool {
-	point_to_light := world.Light().position.Sub(point)
-	distance_to_light := point_to_light.Magnitude()
-	point_to_light_ray := NewRay(point, point_to_light.Normalize())
-
-	intersections := world.IntersectWith(&point_to_light_ray)
-	i, wasHit := Hit(intersections)
-	if !wasHit {
-		return false
+// Color seen through the transparent surface. Returns BLACK for opaque materials, when
+// there are no bounces left or in case of total internal reflection
+func RefractedColor(world *World, comps *IntersectionComputations, remaining int) Color {
+	transparency := comps.intersectionObject.Material().transparency
+	if remaining <= 0 || transparency == 0 {
+		return BLACK
 	}
 
-	return i.time < distance_to_light
+	direction, ok := refractedDirection(comps)
+	if !ok {
+		return BLACK
+	}
+	refractRay := NewRayAtTime(comps.underPoint, direction, comps.rayTime)
+
+	color := world.ColorAtIntersection(refractRay, remaining-1)
+	return color.MultScalar(transparency)
+}
+
+// Direction of the refracted ray, false in case of total internal reflection
+func refractedDirection(comps *IntersectionComputations) (Vec3, bool) {
+	// Snell's law: sin(theta_t) / sin(theta_i) == n1 / n2
+	nRatio := comps.n1 / comps.n2
+	cosI := comps.eyev.Dot(comps.objectNormalv)
+	sin2T := nRatio * nRatio * (1 - cosI*cosI)
+	if sin2T > 1 {
+		return Vec3{}, false
+	}
+
+	cosT := math.Sqrt(1 - sin2T)
+	return comps.objectNormalv.Mul(nRatio*cosI - cosT).Sub(comps.eyev.Mul(nRatio)), true
+}
+
+// Schlick's approximation of Fresnel equations. Returns the fraction of the light
+// which is reflected, the rest of it is refracted
+func Schlick(comps *IntersectionComputations) float64 {
+	cos := comps.eyev.Dot(comps.objectNormalv)
+
+	if comps.n1 > comps.n2 {
+		n := comps.n1 / comps.n2
+		sin2T := n * n * (1 - cos*cos)
+		if sin2T > 1 {
+			// total internal reflection
+			return 1
+		}
+		cos = math.Sqrt(1 - sin2T)
+	}
+
+	r0 := math.Pow((comps.n1-comps.n2)/(comps.n1+comps.n2), 2)
+	return r0 + (1-r0)*math.Pow(1-cos, 5)
+}
+
+// Checks if theres smth between point and the light source. Light with many
+// samples (e.g. AreaLight) is shadowed only if all of them are blocked. Objects
+// with transparentShadow don't shadow the point completely, see LightIntensityAt
+func IsShadowed(world *World, light Light, point Point3) bool {
+	return LightIntensityAt(world, light, point) == 0
+}
+
+// Fraction of the light reaching the point: 0 if it's in shadow and 1 if it's fully lit.
+// For lights with many samples it's the fraction of the visible samples, that's what
+// makes soft shadows. Materials with transparentShadow let some light through, their
+// transparency is accounted for every surface crossed on the way
+func LightIntensityAt(world *World, light Light, point Point3) float64 {
+	return lightIntensityAtTime(world, light, point, 0)
+}
+
+// Same as LightIntensityAt, but moving objects cast shadows from where they are at the moment of time
+func lightIntensityAtTime(world *World, light Light, point Point3, time float64) float64 {
+	samples := light.samplesAt(point)
+	sum := 0.
+	for _, sample := range samples {
+		sum += sampleVisibility(world, point, sample, time)
+	}
+	return sum / float64(len(samples))
+}
+
+func sampleVisibility(world *World, point Point3, sample lightSample, time float64) float64 {
+	pointToLightRay := NewRayAtTime(point, sample.direction, time)
+
+	visibility := 1.
+	for _, i := range world.IntersectWith(&pointToLightRay) {
+		if i.time <= 0 || i.time >= sample.distance {
+			continue
+		}
+
+		material := i.object.Material()
+		if !material.transparentShadow {
+			return 0
+		}
+		visibility *= material.transparency
+	}
+	return visibility
 }
@@ -10,7 +10,7 @@ import (
 var COS45 = math.Sqrt(2) / 2.0
 
 func TestCreatingPointLight(t *testing.T) {
-	pos := NewPoint(0, 0, 0)
+	pos := NewPoint3(0, 0, 0)
 	intensity := WHITE
 
 	pl := NewPointLight(pos, intensity)
@@ -20,117 +20,117 @@ func TestCreatingPointLight(t *testing.T) {
 }
 
 func TestLightingWithEyeBetweenLightAndSurface(t *testing.T) {
-	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint(0, 0, 0)
-	eye := NewVector(0, 0, -1)
-	normal := NewVector(0, 0, -1)
-	light := NewPointLight(NewPoint(0, 0, -10), WHITE)
+	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint3(0, 0, 0)
+	eye := NewVec3(0, 0, -1)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 0, -10), WHITE)
 
 	i := 0.1 + 0.9 + 0.9
 	expect := NewColor(i, i, i)
-	res := CalcLighting(m, light, pos, eye, normal, false)
+	res := CalcLighting(m, nil, light, pos, eye, normal, false)
 
 	require.True(t, res.Equal(expect))
 }
 
 func TestLightingWithEyeOffset45DegreesBetweenLightAndSurface(t *testing.T) {
-	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint(0, 0, 0)
-	eye := NewVector(0, COS45, COS45)
-	normal := NewVector(0, 0, -1)
-	light := NewPointLight(NewPoint(0, 0, -10), WHITE)
+	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint3(0, 0, 0)
+	eye := NewVec3(0, COS45, COS45)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 0, -10), WHITE)
 
 	i := 0.1 + 0.9 + 0
 	expect := NewColor(i, i, i)
-	res := CalcLighting(m, light, pos, eye, normal, false)
+	res := CalcLighting(m, nil, light, pos, eye, normal, false)
 
 	require.True(t, res.Equal(expect))
 }
 
 func TestLightingWithEyeOppositeSurfaceAndLightOffset45Degrees(t *testing.T) {
-	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint(0, 0, 0)
-	eye := NewVector(0, 0, -1)
-	normal := NewVector(0, 0, -1)
-	light := NewPointLight(NewPoint(0, 10, -10), WHITE)
+	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint3(0, 0, 0)
+	eye := NewVec3(0, 0, -1)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 10, -10), WHITE)
 
 	i := 0.1 + 0.9*COS45 + 0
 	expect := NewColor(i, i, i)
-	res := CalcLighting(m, light, pos, eye, normal, false)
+	res := CalcLighting(m, nil, light, pos, eye, normal, false)
 
 	require.True(t, res.Equal(expect))
 }
 
 func TestLightingWithEyeInThePathOfReflectionVector(t *testing.T) {
-	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint(0, 0, 0)
-	eye := NewVector(0, -COS45, -COS45)
-	normal := NewVector(0, 0, -1)
-	light := NewPointLight(NewPoint(0, 10, -10), WHITE)
+	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint3(0, 0, 0)
+	eye := NewVec3(0, -COS45, -COS45)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 10, -10), WHITE)
 
 	i := 0.1 + 0.9*COS45 + 0.9
 	expect := NewColor(i, i, i)
-	res := CalcLighting(m, light, pos, eye, normal, false)
+	res := CalcLighting(m, nil, light, pos, eye, normal, false)
 
 	require.True(t, res.Equal(expect))
 }
 
 func TestLightingWithLightBehindTheSurface(t *testing.T) {
-	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint(0, 0, 0)
-	eye := NewVector(0, 0, -1)
-	normal := NewVector(0, 0, -1)
-	light := NewPointLight(NewPoint(0, 0, 10), WHITE)
+	m, pos := NewMaterial(WHITE, 0.1, 0.9, 0.9, 200.), NewPoint3(0, 0, 0)
+	eye := NewVec3(0, 0, -1)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 0, 10), WHITE)
 
 	i := 0.1 + 0 + 0
 	expect := NewColor(i, i, i)
-	res := CalcLighting(m, light, pos, eye, normal, false)
+	res := CalcLighting(m, nil, light, pos, eye, normal, false)
 
 	require.True(t, res.Equal(expect))
 }
 
 func TestShadingAnIntersectionFromTheOutside(t *testing.T) {
 	w := NewDefaultWorld()
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
 	s1 := w.Sphere("s1")
 	i := NewIntersection(4, s1)
-	comps := PrepareIntersectionComputations(i, r)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
 
 	expect := NewColor(0.38066, 0.47583, 0.2855)
-	res := ShadeHit(w, &comps)
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
 
 	require.True(t, expect.Equal(res))
 }
 
 func TestShadingAnIntersectionFromTheInside(t *testing.T) {
 	w := NewDefaultWorld()
-	w.SetLight(NewPointLight(NewPoint(0, 0.25, 0), WHITE))
-	r := NewRay(NewPoint(0, 0, 0), NewVector(0, 0, 1))
+	w.SetLight(NewPointLight(NewPoint3(0, 0.25, 0), WHITE))
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
 
 	s2 := w.Sphere("s2")
 	i := NewIntersection(0.5, s2)
-	comps := PrepareIntersectionComputations(i, r)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
 
 	expect := NewColor(0.90498, 0.90498, 0.90498)
-	res := ShadeHit(w, &comps)
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
 
 	require.True(t, expect.Equal(res))
 }
 
 func TestOneSphereShadowingPointOfIntersectionWithOtherSphere(t *testing.T) {
 	world := NewWorld()
-	pl := NewPointLight(NewPoint(0, 0, -10), WHITE)
+	pl := NewPointLight(NewPoint3(0, 0, -10), WHITE)
 	world.SetLight(pl)
 
 	default_material := NewDefaultMaterial()
 	s1 := NewSphere("s1", default_material)
-	world["s1"] = &s1
+	world.Add("s1", &s1)
 
 	s2 := NewSphere("s2", default_material)
 	s2.SetTransform(NewTranslationMatrix(0, 0, 10))
-	world["s2"] = &s2
+	world.Add("s2", &s2)
 
-	r := NewRay(NewPoint(0, 0, 5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, 5), NewVec3(0, 0, 1))
 	unit_radius := 1.
 	distance_to_s2 := (10. - unit_radius) - r.origin.z
 	i := NewIntersection(distance_to_s2, &s2)
-	comps := PrepareIntersectionComputations(i, r)
-	color_at_intersection := ShadeHit(world, &comps)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+	color_at_intersection := ShadeHit(world, &comps, MAX_REFLECTION_DEPTH)
 	expected_ambient_color := NewColor(0.1, 0.1, 0.1)
 
 	require.Equal(t, expected_ambient_color, color_at_intersection)
@@ -138,14 +138,14 @@ func TestOneSphereShadowingPointOfIntersectionWithOtherSphere(t *testing.T) {
 
 func TestLightingWithTheSurfaceInShadow(t *testing.T) {
 	ambientColor := 0.1
-	m, pos := NewMaterial(WHITE, ambientColor, 0.9, 0.9, 200.), NewPoint(0, 0, 0)
-	eye := NewVector(0, 0, -1)
-	normal := NewVector(0, 0, -1)
-	light := NewPointLight(NewPoint(0, 0, -10), WHITE)
+	m, pos := NewMaterial(WHITE, ambientColor, 0.9, 0.9, 200.), NewPoint3(0, 0, 0)
+	eye := NewVec3(0, 0, -1)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 0, -10), WHITE)
 	inShadow := true
 
 	expect := NewColor(ambientColor, ambientColor, ambientColor)
-	res := CalcLighting(m, light, pos, eye, normal, inShadow)
+	res := CalcLighting(m, nil, light, pos, eye, normal, inShadow)
 
 	require.True(t, expect.Equal(res))
 }
@@ -154,28 +154,300 @@ func TestLightingWithTheSurfaceInShadow(t *testing.T) {
 func TestPointIsNotShadowedAndNotCollinear(t *testing.T) {
 	w := NewDefaultWorld()
 
-	p := NewPoint(0, 10, 0)
+	p := NewPoint3(0, 10, 0)
 
-	require.False(t, IsShadowed(w, p))
+	require.False(t, IsShadowed(w, w.Light(), p))
 }
 
 func TestPointIsShadowedBySphere(t *testing.T) {
 	w := NewDefaultWorld()
-	p := NewPoint(10, -10, 10)
+	p := NewPoint3(10, -10, 10)
 
-	require.True(t, IsShadowed(w, p))
+	require.True(t, IsShadowed(w, w.Light(), p))
 }
 
 func TestPointIsNotShadowedAndBehindTheLight(t *testing.T) {
 	w := NewDefaultWorld()
-	p := NewPoint(-20, 20, -20)
+	p := NewPoint3(-20, 20, -20)
 
-	require.False(t, IsShadowed(w, p))
+	require.False(t, IsShadowed(w, w.Light(), p))
 }
 
 func TestPointIsNotShadowedAndBetweenTheLightAndSphere(t *testing.T) {
 	w := NewDefaultWorld()
-	p := NewPoint(-2, 2, -2)
+	p := NewPoint3(-2, 2, -2)
 
-	require.False(t, IsShadowed(w, p))
+	require.False(t, IsShadowed(w, w.Light(), p))
+}
+
+// Plane below the default world, which reflects half of the light
+func newReflectiveFloor() Plane {
+	m := NewDefaultMaterial()
+	m.reflective = 0.5
+	p := NewPlane("floor", m)
+	p.SetTransform(NewTranslationMatrix(0, -1, 0))
+	return p
+}
+
+func TestReflectedColorForNonreflectiveMaterialIsBlack(t *testing.T) {
+	w := NewDefaultWorld()
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+	s2 := w.Sphere("s2")
+	s2.material.ambient = 1
+	i := NewIntersection(1, s2)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	res := ReflectedColor(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.Equal(t, BLACK, res)
+}
+
+func TestReflectedColorForReflectiveMaterial(t *testing.T) {
+	w := NewDefaultWorld()
+	floor := newReflectiveFloor()
+	w.Add("floor", &floor)
+	r := NewRay(NewPoint3(0, 0, -3), NewVec3(0, -COS45, COS45))
+	i := NewIntersection(math.Sqrt2, &floor)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	res := ReflectedColor(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.True(t, res.Equal(NewColor(0.19033, 0.23791, 0.14274)), res)
+}
+
+func TestShadeHitWithReflectiveMaterial(t *testing.T) {
+	w := NewDefaultWorld()
+	floor := newReflectiveFloor()
+	w.Add("floor", &floor)
+	r := NewRay(NewPoint3(0, 0, -3), NewVec3(0, -COS45, COS45))
+	i := NewIntersection(math.Sqrt2, &floor)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.True(t, res.Equal(NewColor(0.87676, 0.92434, 0.82918)), res)
+}
+
+func TestReflectedColorAtTheMaximumRecursiveDepthIsBlack(t *testing.T) {
+	w := NewDefaultWorld()
+	floor := newReflectiveFloor()
+	w.Add("floor", &floor)
+	r := NewRay(NewPoint3(0, 0, -3), NewVec3(0, -COS45, COS45))
+	i := NewIntersection(math.Sqrt2, &floor)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	res := ReflectedColor(w, &comps, 0)
+
+	require.Equal(t, BLACK, res)
+}
+
+func TestMutuallyReflectiveSurfacesTerminate(t *testing.T) {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(0, 0, 0), WHITE))
+	m := NewDefaultMaterial()
+	m.reflective = 1
+	lower := NewPlane("lower", m)
+	lower.SetTransform(NewTranslationMatrix(0, -1, 0))
+	w.Add("lower", &lower)
+	upper := NewPlane("upper", m)
+	upper.SetTransform(NewTranslationMatrix(0, 1, 0))
+	w.Add("upper", &upper)
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 1, 0))
+
+	res := w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH)
+
+	// getting here at all means the recursion has stopped
+	require.False(t, res.Equal(BLACK))
+}
+
+func TestRefractedColorWithOpaqueSurfaceIsBlack(t *testing.T) {
+	w := NewDefaultWorld()
+	s1 := w.Sphere("s1")
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	xs := []Intersection{NewIntersection(4, s1), NewIntersection(6, s1)}
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+
+	res := RefractedColor(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.Equal(t, BLACK, res)
+}
+
+func TestRefractedColorAtTheMaximumRecursiveDepthIsBlack(t *testing.T) {
+	w := NewDefaultWorld()
+	s1 := w.Sphere("s1")
+	s1.material.transparency = 1
+	s1.material.refractiveIndex = 1.5
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	xs := []Intersection{NewIntersection(4, s1), NewIntersection(6, s1)}
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+
+	res := RefractedColor(w, &comps, 0)
+
+	require.Equal(t, BLACK, res)
+}
+
+func TestRefractedColorUnderTotalInternalReflectionIsBlack(t *testing.T) {
+	w := NewDefaultWorld()
+	s1 := w.Sphere("s1")
+	s1.material.transparency = 1
+	s1.material.refractiveIndex = 1.5
+	r := NewRay(NewPoint3(0, 0, COS45), NewVec3(0, 1, 0))
+	xs := []Intersection{NewIntersection(-COS45, s1), NewIntersection(COS45, s1)}
+	// the ray is inside the sphere, so the second intersection is the hit
+	comps := PrepareIntersectionComputations(xs[1], r, xs)
+
+	res := RefractedColor(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.Equal(t, BLACK, res)
+}
+
+// Transparent floor with a red ball below it
+func newWorldWithTransparentFloor() (*World, *Plane) {
+	w := NewDefaultWorld()
+
+	floorMaterial := NewDefaultMaterial()
+	floorMaterial.transparency = 0.5
+	floorMaterial.refractiveIndex = 1.5
+	floor := NewPlane("floor", floorMaterial)
+	floor.SetTransform(NewTranslationMatrix(0, -1, 0))
+	w.Add("floor", &floor)
+
+	ballMaterial := NewDefaultMaterial()
+	ballMaterial.color = RED
+	ballMaterial.ambient = 0.5
+	ball := NewSphere("ball", ballMaterial)
+	ball.SetTransform(NewTranslationMatrix(0, -3.5, -0.5))
+	w.Add("ball", &ball)
+
+	return w, &floor
+}
+
+func TestShadeHitWithTransparentMaterial(t *testing.T) {
+	w, floor := newWorldWithTransparentFloor()
+	r := NewRay(NewPoint3(0, 0, -3), NewVec3(0, -COS45, COS45))
+	xs := []Intersection{NewIntersection(math.Sqrt2, floor)}
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.True(t, res.Equal(NewColor(0.93642, 0.68642, 0.68642)), res)
+}
+
+func TestShadeHitWithReflectiveTransparentMaterial(t *testing.T) {
+	w, floor := newWorldWithTransparentFloor()
+	floor.material.reflective = 0.5
+	r := NewRay(NewPoint3(0, 0, -3), NewVec3(0, -COS45, COS45))
+	xs := []Intersection{NewIntersection(math.Sqrt2, floor)}
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.True(t, res.Equal(NewColor(0.93391, 0.69643, 0.69243)), res)
+}
+
+func TestSchlickApproximationUnderTotalInternalReflection(t *testing.T) {
+	s := newGlassSphere("s")
+	r := NewRay(NewPoint3(0, 0, COS45), NewVec3(0, 1, 0))
+	xs := []Intersection{NewIntersection(-COS45, &s), NewIntersection(COS45, &s)}
+	comps := PrepareIntersectionComputations(xs[1], r, xs)
+
+	require.EqualValues(t, 1, Schlick(&comps))
+}
+
+func TestSchlickApproximationWithPerpendicularViewingAngle(t *testing.T) {
+	s := newGlassSphere("s")
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 1, 0))
+	xs := []Intersection{NewIntersection(-1, &s), NewIntersection(1, &s)}
+	comps := PrepareIntersectionComputations(xs[1], r, xs)
+
+	require.InDelta(t, 0.04, Schlick(&comps), EPSILON)
+}
+
+func TestSchlickApproximationWithSmallAngleAndBiggerN2(t *testing.T) {
+	s := newGlassSphere("s")
+	r := NewRay(NewPoint3(0, 0.99, -2), NewVec3(0, 0, 1))
+	xs := []Intersection{NewIntersection(1.8589, &s)}
+	comps := PrepareIntersectionComputations(xs[0], r, xs)
+
+	require.InDelta(t, 0.48873, Schlick(&comps), EPSILON)
+}
+
+func TestLightIntensityAtPointBehindOpaqueObjectIsZero(t *testing.T) {
+	w := NewDefaultWorld()
+
+	require.EqualValues(t, 0, LightIntensityAt(w, w.Light(), NewPoint3(10, -10, 10)))
+	require.EqualValues(t, 1, LightIntensityAt(w, w.Light(), NewPoint3(0, 10, 0)))
+}
+
+func TestTransparentObjectsMayCastLighterShadows(t *testing.T) {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(0, 0, -10), WHITE))
+	s := newGlassSphere("s")
+	s.material.transparency = 0.5
+	w.Add("s", &s)
+	point := NewPoint3(0, 0, 10)
+
+	require.EqualValues(t, 0, LightIntensityAt(w, w.Light(), point))
+	require.True(t, IsShadowed(w, w.Light(), point))
+
+	s.material.transparentShadow = true
+	// light goes through two surfaces of the sphere
+	require.InDelta(t, 0.25, LightIntensityAt(w, w.Light(), point), EPSILON)
+	require.False(t, IsShadowed(w, w.Light(), point))
+}
+
+func TestLightingWithPatternApplied(t *testing.T) {
+	m := NewMaterial(WHITE, 1, 0, 0, 200)
+	m.pattern = NewStripePattern(WHITE, BLACK)
+	s := NewDefaultSphere()
+	eye := NewVec3(0, 0, -1)
+	normal := NewVec3(0, 0, -1)
+	light := NewPointLight(NewPoint3(0, 0, -10), WHITE)
+
+	c1 := CalcLighting(m, &s, light, NewPoint3(0.9, 0, 0), eye, normal, false)
+	c2 := CalcLighting(m, &s, light, NewPoint3(1.1, 0, 0), eye, normal, false)
+
+	require.True(t, c1.Equal(WHITE))
+	require.True(t, c2.Equal(BLACK))
+}
+
+func TestShadeHitSumsContributionsOfAllLights(t *testing.T) {
+	w := NewDefaultWorld()
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	i := NewIntersection(4, w.Sphere("s1"))
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+	single := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	w.AddLight("copy", w.Light())
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	require.True(t, res.Equal(single.MultScalar(2)), res)
+}
+
+func TestShadowsAreCheckedForEveryLight(t *testing.T) {
+	w := NewWorld()
+	floor := NewDefaultPlane()
+	w.Add("floor", &floor)
+	blocker := NewDefaultSphere()
+	blocker.SetTransform(NewTranslationMatrix(0, 5, 0))
+	w.Add("blocker", &blocker)
+	blocked := NewPointLight(NewPoint3(0, 10, 0), WHITE)
+	w.AddLight("blocked", blocked)
+	visible := NewPointLight(NewPoint3(10, 10, 0), WHITE)
+	w.AddLight("visible", visible)
+	point := NewPoint3(0, EPSILON, 0)
+
+	require.True(t, IsShadowed(w, blocked, point))
+	require.False(t, IsShadowed(w, visible, point))
+
+	r := NewRay(NewPoint3(0, 1, 0), NewVec3(0, -1, 0))
+	i := NewIntersection(1, &floor)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+	res := ShadeHit(w, &comps, MAX_REFLECTION_DEPTH)
+
+	m := floor.Material()
+	eye, normal := NewVec3(0, 1, 0), NewVec3(0, 1, 0)
+	expect := CalcLighting(m, &floor, blocked, comps.overPoint, eye, normal, true).
+		Add(CalcLighting(m, &floor, visible, comps.overPoint, eye, normal, false))
+	require.True(t, res.Equal(expect), res)
 }
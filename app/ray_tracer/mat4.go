@@ -0,0 +1,166 @@
+package ray_tracer
+
+import "fmt"
+
+// Fixed size 4x4 matrix stored by rows. Unlike Matrix it's a value type, so
+// multiplications and inversions don't allocate. Used on the hot paths of rendering
+type Mat4 [16]float64
+
+func NewIdentityMat4() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func (a *Matrix) ToMat4() Mat4 {
+	if a.rows != 4 || a.columns != 4 {
+		panic(fmt.Sprintf("Can't convert matrix [%d, %d] to Mat4!", a.rows, a.columns))
+	}
+
+	m := Mat4{}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			m[i*4+j] = a.data[i][j]
+		}
+	}
+	return m
+}
+
+func (m *Mat4) ToMatrix() *Matrix {
+	res := NewZeroMatrix(4, 4)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			res.data[i][j] = m[i*4+j]
+		}
+	}
+	return res
+}
+
+func (m *Mat4) At(row int, col int) float64 {
+	return m[row*4+col]
+}
+
+func (a *Mat4) Equal(b *Mat4) bool {
+	for i := range a {
+		if !equal_fp(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *Mat4) Mul(b *Mat4) Mat4 {
+	res := Mat4{}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			res[i*4+j] = a[i*4]*b[j] + a[i*4+1]*b[4+j] + a[i*4+2]*b[8+j] + a[i*4+3]*b[12+j]
+		}
+	}
+	return res
+}
+
+func (m *Mat4) MulTuple(t Tuple) Tuple {
+	return Tuple{
+		m[0]*t.x + m[1]*t.y + m[2]*t.z + m[3]*t.w,
+		m[4]*t.x + m[5]*t.y + m[6]*t.z + m[7]*t.w,
+		m[8]*t.x + m[9]*t.y + m[10]*t.z + m[11]*t.w,
+		m[12]*t.x + m[13]*t.y + m[14]*t.z + m[15]*t.w,
+	}
+}
+
+func (m *Mat4) Transpose() Mat4 {
+	return Mat4{
+		m[0], m[4], m[8], m[12],
+		m[1], m[5], m[9], m[13],
+		m[2], m[6], m[10], m[14],
+		m[3], m[7], m[11], m[15],
+	}
+}
+
+// 2x2 determinants of the top two rows (s) and the bottom two rows (c).
+// Both the determinant and the inverse are expressed through them
+func (m *Mat4) subDeterminants() (s, c [6]float64) {
+	s[0] = m[0]*m[5] - m[4]*m[1]
+	s[1] = m[0]*m[6] - m[4]*m[2]
+	s[2] = m[0]*m[7] - m[4]*m[3]
+	s[3] = m[1]*m[6] - m[5]*m[2]
+	s[4] = m[1]*m[7] - m[5]*m[3]
+	s[5] = m[2]*m[7] - m[6]*m[3]
+
+	c[0] = m[8]*m[13] - m[12]*m[9]
+	c[1] = m[8]*m[14] - m[12]*m[10]
+	c[2] = m[8]*m[15] - m[12]*m[11]
+	c[3] = m[9]*m[14] - m[13]*m[10]
+	c[4] = m[9]*m[15] - m[13]*m[11]
+	c[5] = m[10]*m[15] - m[14]*m[11]
+	return s, c
+}
+
+func determinantFromSubDeterminants(s, c [6]float64) float64 {
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+func (m *Mat4) Determinant() float64 {
+	return determinantFromSubDeterminants(m.subDeterminants())
+}
+
+func (m *Mat4) IsInvertible() bool {
+	return m.Determinant() != 0
+}
+
+// Closed form inverse (Laplace expansion by the top two and the bottom two rows)
+func (m *Mat4) Inverse() Mat4 {
+	s, c := m.subDeterminants()
+	det := determinantFromSubDeterminants(s, c)
+	if det == 0 {
+		panic("Trying to invert non-invertible matrix!")
+	}
+
+	inv := 1 / det
+	return Mat4{
+		(m[5]*c[5] - m[6]*c[4] + m[7]*c[3]) * inv,
+		(-m[1]*c[5] + m[2]*c[4] - m[3]*c[3]) * inv,
+		(m[13]*s[5] - m[14]*s[4] + m[15]*s[3]) * inv,
+		(-m[9]*s[5] + m[10]*s[4] - m[11]*s[3]) * inv,
+
+		(-m[4]*c[5] + m[6]*c[2] - m[7]*c[1]) * inv,
+		(m[0]*c[5] - m[2]*c[2] + m[3]*c[1]) * inv,
+		(-m[12]*s[5] + m[14]*s[2] - m[15]*s[1]) * inv,
+		(m[8]*s[5] - m[10]*s[2] + m[11]*s[1]) * inv,
+
+		(m[4]*c[4] - m[5]*c[2] + m[7]*c[0]) * inv,
+		(-m[0]*c[4] + m[1]*c[2] - m[3]*c[0]) * inv,
+		(m[12]*s[4] - m[13]*s[2] + m[15]*s[0]) * inv,
+		(-m[8]*s[4] + m[9]*s[2] - m[11]*s[0]) * inv,
+
+		(-m[4]*c[3] + m[5]*c[1] - m[6]*c[0]) * inv,
+		(m[0]*c[3] - m[1]*c[1] + m[2]*c[0]) * inv,
+		(-m[12]*s[3] + m[13]*s[1] - m[14]*s[0]) * inv,
+		(m[8]*s[3] - m[9]*s[1] + m[10]*s[0]) * inv,
+	}
+}
+
+func (m Mat4) String() string {
+	return m.ToMatrix().String()
+}
+
+// Points are affected by translation, so w == 1 is implied
+func (m *Mat4) MulPoint(p Point3) Point3 {
+	return Point3{
+		m[0]*p.x + m[1]*p.y + m[2]*p.z + m[3],
+		m[4]*p.x + m[5]*p.y + m[6]*p.z + m[7],
+		m[8]*p.x + m[9]*p.y + m[10]*p.z + m[11],
+	}
+}
+
+// Vectors aren't affected by translation, so w == 0 is implied
+func (m *Mat4) MulVec(v Vec3) Vec3 {
+	return Vec3{
+		m[0]*v.x + m[1]*v.y + m[2]*v.z,
+		m[4]*v.x + m[5]*v.y + m[6]*v.z,
+		m[8]*v.x + m[9]*v.y + m[10]*v.z,
+	}
+}
@@ -0,0 +1,175 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMatrixA() *Matrix {
+	return NewMatrix([][]float64{
+		{-5, 2, 6, -8},
+		{1, -5, 1, 8},
+		{7, 7, -6, -7},
+		{1, -3, 7, 4},
+	})
+}
+
+func newTestMatrixB() *Matrix {
+	return NewMatrix([][]float64{
+		{8, -5, 9, 2},
+		{7, 5, 6, 1},
+		{-6, 0, 9, 6},
+		{-3, 0, -9, -4},
+	})
+}
+
+func TestConvertingMatrixToMat4AndBack(t *testing.T) {
+	a := newTestMatrixA()
+
+	m := a.ToMat4()
+
+	require.EqualValues(t, -5, m.At(0, 0))
+	require.EqualValues(t, 8, m.At(1, 3))
+	require.EqualValues(t, 7, m.At(3, 2))
+	require.True(t, a.Equal(m.ToMatrix()))
+}
+
+func TestConvertingNon4x4MatrixToMat4Panics(t *testing.T) {
+	require.Panics(t, func() { NewIdentityMatrix(3).ToMat4() })
+}
+
+func TestIdentityMat4(t *testing.T) {
+	m := NewIdentityMat4()
+
+	require.True(t, NewIdentityMatrix(4).Equal(m.ToMatrix()))
+}
+
+func TestMultiplyingMat4s(t *testing.T) {
+	a, b := newTestMatrixA().ToMat4(), newTestMatrixB().ToMat4()
+
+	res := a.Mul(&b)
+
+	expect := newTestMatrixA().MulMat(newTestMatrixB())
+	require.True(t, expect.Equal(res.ToMatrix()))
+}
+
+func TestMultiplyingMat4ByTuple(t *testing.T) {
+	m := NewIdentityMatrix(4).RotateX(math.Pi/3).Scale(1, 2, 3).Translate(4, 5, 6)
+	m4 := m.ToMat4()
+	tuple := NewTuple(1, 2, 3, 1)
+
+	require.True(t, m.MulTuple(tuple).Equal(m4.MulTuple(tuple)))
+}
+
+func TestTransposingMat4(t *testing.T) {
+	a := newTestMatrixA()
+	m := a.ToMat4()
+
+	res := m.Transpose()
+
+	require.True(t, a.Transpose().Equal(res.ToMatrix()))
+}
+
+func TestMat4Determinant(t *testing.T) {
+	for _, a := range []*Matrix{newTestMatrixA(), newTestMatrixB(), NewIdentityMatrix(4)} {
+		m := a.ToMat4()
+
+		require.InDelta(t, a.Determinant(), m.Determinant(), EPSILON)
+	}
+}
+
+func TestInvertingMat4(t *testing.T) {
+	matrices := []*Matrix{
+		newTestMatrixA(),
+		newTestMatrixB(),
+		NewMatrix([][]float64{
+			{9, 3, 0, 9},
+			{-5, -2, -6, -3},
+			{-4, 9, 6, 4},
+			{-7, 6, 6, 2},
+		}),
+		NewIdentityMatrix(4).RotateY(1).Shear(1, 0, 0.5, 0, 0, 2).Translate(1, -2, 3),
+	}
+
+	for _, a := range matrices {
+		m := a.ToMat4()
+
+		inverse := m.Inverse()
+
+		require.True(t, a.Inverse().Equal(inverse.ToMatrix()), "inverse of\n%s", a)
+		identity := NewIdentityMat4()
+		product := m.Mul(&inverse)
+		require.True(t, identity.Equal(&product))
+	}
+}
+
+func TestInvertingNonInvertibleMat4Panics(t *testing.T) {
+	m := NewScalingMatrix(0, 1, 1).ToMat4()
+
+	require.False(t, m.IsInvertible())
+	require.Panics(t, func() { m.Inverse() })
+}
+
+func TestMat4OperationsDontAllocate(t *testing.T) {
+	a, b := newTestMatrixA().ToMat4(), newTestMatrixB().ToMat4()
+	tuple := NewPoint(1, 2, 3)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		product := a.Mul(&b)
+		inverse := product.Inverse()
+		transposed := inverse.Transpose()
+		tuple = transposed.MulTuple(tuple)
+	})
+
+	require.Zero(t, allocs)
+}
+
+func TestSettingTransformCachesInverse(t *testing.T) {
+	s := NewDefaultSphere()
+	transform := NewTranslationMatrix(1, 2, 3).MulMat(NewScalingMatrix(2, 2, 2))
+
+	s.SetTransform(transform)
+
+	expectInverse := transform.Inverse()
+	inverse, normal := s.inverseTransform(0), s.normalTransform(0)
+	require.True(t, expectInverse.Equal(inverse.ToMatrix()))
+	require.True(t, expectInverse.Transpose().Equal(normal.ToMatrix()))
+}
+
+func TestChangingMatrixAfterSetTransformDoesntAffectShape(t *testing.T) {
+	s := NewDefaultSphere()
+	transform := NewScalingMatrix(2, 2, 2)
+	s.SetTransform(transform)
+
+	transform.Mul(10)
+
+	shapeTransform := s.Transform()
+	require.True(t, shapeTransform.Equal(NewScalingMatrix(2, 2, 2)))
+}
+
+func TestSettingCameraTransformCachesInverse(t *testing.T) {
+	c := NewCamera(11, 11, math.Pi/2)
+	transform := NewRotationYMatrix(math.Pi / 4).MulMat(NewTranslationMatrix(0, -2, 5))
+
+	c.SetTransform(transform)
+
+	cameraTransform := c.Transform()
+	require.True(t, transform.Equal(&cameraTransform))
+	require.True(t, transform.Inverse().Equal(c.inverse.ToMatrix()))
+}
+
+func BenchmarkMatrixInverse(b *testing.B) {
+	m := newTestMatrixA()
+	for i := 0; i < b.N; i++ {
+		m.Inverse()
+	}
+}
+
+func BenchmarkMat4Inverse(b *testing.B) {
+	m := newTestMatrixA().ToMat4()
+	for i := 0; i < b.N; i++ {
+		m.Inverse()
+	}
+}
@@ -1,11 +1,30 @@
 package ray_tracer
 
+// Refractive indices of some common materials
+const (
+	VACUUM_REFRACTIVE_INDEX  = 1.0
+	AIR_REFRACTIVE_INDEX     = 1.00029
+	WATER_REFRACTIVE_INDEX   = 1.333
+	GLASS_REFRACTIVE_INDEX   = 1.52
+	DIAMOND_REFRACTIVE_INDEX = 2.417
+)
+
 type Material struct {
-	color     Color
+	color Color
+	// Overrides the color, if set
+	pattern   Pattern
 	ambient   float64
 	diffuse   float64
 	specular  float64
 	shininess float64
+	// 0 is completely nonreflective, 1 is a perfect mirror
+	reflective float64
+	// 0 is completely opaque, 1 lets all the light through
+	transparency    float64
+	refractiveIndex float64
+	// Transparent objects let some light through their shadows, if set.
+	// Otherwise every object casts a full shadow
+	transparentShadow bool
 }
 
 func NewMaterial(color Color, ambient, diffuse, specular, shininess float64) Material {
@@ -13,9 +32,24 @@ func NewMaterial(color Color, ambient, diffuse, specular, shininess float64) Mat
 		panic("All Material's attribues must be nonnegative!")
 	}
 
-	return Material{color, ambient, diffuse, specular, shininess}
+	return Material{color: color, ambient: ambient, diffuse: diffuse, specular: specular, shininess: shininess,
+		refractiveIndex: VACUUM_REFRACTIVE_INDEX}
 }
 
 func NewDefaultMaterial() Material {
-	return Material{WHITE, 0.1, 0.9, 0.9, 200.}
+	return Material{color: WHITE, ambient: 0.1, diffuse: 0.9, specular: 0.9, shininess: 200.,
+		refractiveIndex: VACUUM_REFRACTIVE_INDEX}
+}
+
+// Clear glass, which both reflects and refracts the light
+func NewGlassMaterial() Material {
+	m := NewDefaultMaterial()
+	m.color = BLACK
+	m.diffuse = 0.1
+	m.specular = 1
+	m.shininess = 300
+	m.reflective = 0.9
+	m.transparency = 0.9
+	m.refractiveIndex = GLASS_REFRACTIVE_INDEX
+	return m
 }
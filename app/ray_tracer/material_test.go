@@ -22,3 +22,11 @@ func TestMaterialArgumentsMustBePositive(t *testing.T) {
 	require.Panics(t, func() { NewMaterial(WHITE, 0, 0, -2, 0) })
 	require.Panics(t, func() { NewMaterial(WHITE, 0, 0, 0, -2) })
 }
+
+func TestGlassMaterialIsTransparentAndReflective(t *testing.T) {
+	m := NewGlassMaterial()
+
+	require.Greater(t, m.transparency, 0.)
+	require.Greater(t, m.reflective, 0.)
+	require.EqualValues(t, GLASS_REFRACTIVE_INDEX, m.refractiveIndex)
+}
@@ -134,6 +134,14 @@ func (a *Matrix) MulTuple(t Tuple) Tuple {
 	return res.ToTuple()
 }
 
+func (a *Matrix) MulPoint(p Point3) Point3 {
+	return a.MulTuple(p.ToTuple()).ToPoint3()
+}
+
+func (a *Matrix) MulVec(v Vec3) Vec3 {
+	return a.MulTuple(v.ToTuple()).ToVec3()
+}
+
 func (a *Matrix) Transpose() *Matrix {
 	transposed := NewZeroMatrix(a.columns, a.rows)
 	for i := 0; i < a.rows; i++ {
@@ -85,14 +85,7 @@ func (a *Matrix) Shear(xToY, xToZ, yToX, yToZ, zToX, zToY float64) *Matrix {
 
 // World's default orientation is looks from the origin to Z axis in negative direction
 // with UP in the positive Y direction.
-func NewViewTransformation(from, to, up Tuple) *Matrix {
-	if !from.IsPoint() || !to.IsPoint() {
-		panic("From and To must be points!")
-	}
-	if !up.IsVector() {
-		panic("UP must be a vector")
-	}
-
+func NewViewTransformation(from, to Point3, up Vec3) *Matrix {
 	forward := to.Sub(from).Normalize()
 	left := forward.Cross(up.Normalize())
 	trueUp := left.Cross(forward)
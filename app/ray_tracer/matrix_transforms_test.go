@@ -206,7 +206,7 @@ func TestScaleAndRotationOrderDoesntMatter(t *testing.T) {
 }
 
 func TestViewTransformationForDefaultOrientationIsIdentity(t *testing.T) {
-	from, to, up := NewPoint(0, 0, 0), NewPoint(0, 0, -1), NewVector(0, 1, 0)
+	from, to, up := NewPoint3(0, 0, 0), NewPoint3(0, 0, -1), NewVec3(0, 1, 0)
 
 	viewTransform := NewViewTransformation(from, to, up)
 
@@ -214,7 +214,7 @@ func TestViewTransformationForDefaultOrientationIsIdentity(t *testing.T) {
 }
 
 func TestViewTransformWhenLookingBack(t *testing.T) {
-	from, to, up := NewPoint(0, 0, 0), NewPoint(0, 0, +1), NewVector(0, 1, 0)
+	from, to, up := NewPoint3(0, 0, 0), NewPoint3(0, 0, +1), NewVec3(0, 1, 0)
 
 	expect := NewIdentityMatrix(4).Scale(-1, 1, -1)
 	viewTransform := NewViewTransformation(from, to, up)
@@ -223,7 +223,7 @@ func TestViewTransformWhenLookingBack(t *testing.T) {
 }
 
 func TestViewTransformationMovesTheWorldAndNotTheEye(t *testing.T) {
-	from, to, up := NewPoint(0, 0, 8), NewPoint(0, 0, 0), NewVector(0, 1, 0)
+	from, to, up := NewPoint3(0, 0, 8), NewPoint3(0, 0, 0), NewVec3(0, 1, 0)
 
 	// the whole world is moved 8 units away from the eye positioned at the origin
 	expect := NewTranslationMatrix(0, 0, -8)
@@ -233,7 +233,7 @@ func TestViewTransformationMovesTheWorldAndNotTheEye(t *testing.T) {
 }
 
 func TestArbitraryViewTransform(t *testing.T) {
-	from, to, up := NewPoint(1, 3, 2), NewPoint(4, -2, 8), NewVector(1, 1, 0)
+	from, to, up := NewPoint3(1, 3, 2), NewPoint3(4, -2, 8), NewVec3(1, 1, 0)
 
 	expect := NewMatrix([][]float64{
 		{-0.50709, 0.50709, 0.67612, -2.36643},
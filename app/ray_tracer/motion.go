@@ -0,0 +1,233 @@
+package ray_tracer
+
+import (
+	"math"
+	"sort"
+)
+
+// Transform of a moving shape at the moment of time
+type Keyframe struct {
+	time      float64
+	transform Matrix
+}
+
+// The matrix is copied, so changing it afterwards doesn't affect the keyframe
+func NewKeyframe(time float64, transform *Matrix) Keyframe {
+	return Keyframe{time: time, transform: *transform.Copy()}
+}
+
+func (k Keyframe) Time() float64 {
+	return k.time
+}
+
+// Returns a copy, so changing it doesn't affect the keyframe
+func (k Keyframe) Transform() Matrix {
+	return *k.transform.Copy()
+}
+
+// Rotation as a unit quaternion. Unlike matrices, quaternions can be interpolated
+// without squishing the shape in between
+type quaternion struct {
+	w, x, y, z float64
+}
+
+// The matrix must be orthonormal
+func quaternionFromRotation(m *Mat4) quaternion {
+	m00, m11, m22 := m.At(0, 0), m.At(1, 1), m.At(2, 2)
+	var q quaternion
+	// the largest component is calculated first, it keeps the division stable
+	switch trace := m00 + m11 + m22; {
+	case trace > 0:
+		s := math.Sqrt(trace+1) * 2
+		q = quaternion{s / 4, (m.At(2, 1) - m.At(1, 2)) / s, (m.At(0, 2) - m.At(2, 0)) / s, (m.At(1, 0) - m.At(0, 1)) / s}
+	case m00 > m11 && m00 > m22:
+		s := math.Sqrt(1+m00-m11-m22) * 2
+		q = quaternion{(m.At(2, 1) - m.At(1, 2)) / s, s / 4, (m.At(0, 1) + m.At(1, 0)) / s, (m.At(0, 2) + m.At(2, 0)) / s}
+	case m11 > m22:
+		s := math.Sqrt(1+m11-m00-m22) * 2
+		q = quaternion{(m.At(0, 2) - m.At(2, 0)) / s, (m.At(0, 1) + m.At(1, 0)) / s, s / 4, (m.At(1, 2) + m.At(2, 1)) / s}
+	default:
+		s := math.Sqrt(1+m22-m00-m11) * 2
+		q = quaternion{(m.At(1, 0) - m.At(0, 1)) / s, (m.At(0, 2) + m.At(2, 0)) / s, (m.At(1, 2) + m.At(2, 1)) / s, s / 4}
+	}
+	return q.normalize()
+}
+
+func (q quaternion) dot(other quaternion) float64 {
+	return q.w*other.w + q.x*other.x + q.y*other.y + q.z*other.z
+}
+
+func (q quaternion) normalize() quaternion {
+	l := math.Sqrt(q.dot(q))
+	return quaternion{q.w / l, q.x / l, q.y / l, q.z / l}
+}
+
+// Spherical interpolation: the rotation goes with the constant speed along the shortest arc
+func (q quaternion) slerp(other quaternion, t float64) quaternion {
+	cos := q.dot(other)
+	// q and -q are the same rotation, the closer one is taken
+	if cos < 0 {
+		other, cos = quaternion{-other.w, -other.x, -other.y, -other.z}, -cos
+	}
+
+	a, b := 1-t, t
+	// almost the same rotations, linear interpolation is precise enough
+	if cos < 1-EPSILON {
+		angle := math.Acos(cos)
+		a, b = math.Sin((1-t)*angle)/math.Sin(angle), math.Sin(t*angle)/math.Sin(angle)
+	}
+	return quaternion{
+		a*q.w + b*other.w,
+		a*q.x + b*other.x,
+		a*q.y + b*other.y,
+		a*q.z + b*other.z,
+	}.normalize()
+}
+
+// Transform split into translation * rotation * scaling
+type decomposedTransform struct {
+	translation Vec3
+	rotation    quaternion
+	scale       Vec3
+}
+
+// Shearing can't be represented, so it's lost
+func decomposeTransform(m *Mat4) decomposedTransform {
+	x := NewVec3(m.At(0, 0), m.At(1, 0), m.At(2, 0))
+	y := NewVec3(m.At(0, 1), m.At(1, 1), m.At(2, 1))
+	z := NewVec3(m.At(0, 2), m.At(1, 2), m.At(2, 2))
+	scale := NewVec3(x.Magnitude(), y.Magnitude(), z.Magnitude())
+
+	// Gram-Schmidt makes the axes orthonormal, in case there is some shearing
+	x = x.Normalize()
+	y = y.Sub(x.Mul(x.Dot(y))).Normalize()
+	z = x.Cross(y)
+	// mirroring can't be a rotation, so it goes into the scale
+	if m.Determinant() < 0 {
+		scale.z = -scale.z
+	}
+
+	rotation := Mat4{
+		x.x, y.x, z.x, 0,
+		x.y, y.y, z.y, 0,
+		x.z, y.z, z.z, 0,
+		0, 0, 0, 1,
+	}
+	return decomposedTransform{
+		translation: NewVec3(m.At(0, 3), m.At(1, 3), m.At(2, 3)),
+		rotation:    quaternionFromRotation(&rotation),
+		scale:       scale,
+	}
+}
+
+func (d decomposedTransform) interpolate(other decomposedTransform, t float64) decomposedTransform {
+	return decomposedTransform{
+		translation: d.translation.Add(other.translation.Sub(d.translation).Mul(t)),
+		rotation:    d.rotation.slerp(other.rotation, t),
+		scale:       d.scale.Add(other.scale.Sub(d.scale).Mul(t)),
+	}
+}
+
+func (d decomposedTransform) toMat4() Mat4 {
+	q := d.rotation
+	s := d.scale
+	return Mat4{
+		(1 - 2*(q.y*q.y+q.z*q.z)) * s.x, 2 * (q.x*q.y - q.z*q.w) * s.y, 2 * (q.x*q.z + q.y*q.w) * s.z, d.translation.x,
+		2 * (q.x*q.y + q.z*q.w) * s.x, (1 - 2*(q.x*q.x+q.z*q.z)) * s.y, 2 * (q.y*q.z - q.x*q.w) * s.z, d.translation.y,
+		2 * (q.x*q.z - q.y*q.w) * s.x, 2 * (q.y*q.z + q.x*q.w) * s.y, (1 - 2*(q.x*q.x+q.y*q.y)) * s.z, d.translation.z,
+		0, 0, 0, 1,
+	}
+}
+
+type motionKeyframe struct {
+	time       float64
+	transform  Mat4
+	inverse    Mat4
+	decomposed decomposedTransform
+}
+
+// Keyframes of a moving shape, sorted by time. Everything needed for interpolation
+// is calculated once, when the motion is set
+type motion struct {
+	keyframes []motionKeyframe
+}
+
+func newMotion(keyframes []Keyframe) *motion {
+	m := &motion{}
+	for _, k := range keyframes {
+		transform := k.transform.ToMat4()
+		m.keyframes = append(m.keyframes, motionKeyframe{
+			time:       k.time,
+			transform:  transform,
+			inverse:    transform.Inverse(),
+			decomposed: decomposeTransform(&transform),
+		})
+	}
+	sort.SliceStable(m.keyframes, func(i, j int) bool { return m.keyframes[i].time < m.keyframes[j].time })
+	return m
+}
+
+// Index of the keyframe the segment containing the time starts at and the position
+// in the segment in [0, 1]. Before the first and after the last keyframe the shape stays still
+func (m *motion) segmentAt(time float64) (int, float64) {
+	last := len(m.keyframes) - 1
+	if time <= m.keyframes[0].time {
+		return 0, 0
+	}
+	if time >= m.keyframes[last].time {
+		return last, 0
+	}
+
+	i := sort.Search(len(m.keyframes), func(i int) bool { return m.keyframes[i].time > time }) - 1
+	return i, (time - m.keyframes[i].time) / (m.keyframes[i+1].time - m.keyframes[i].time)
+}
+
+func (m *motion) transformAt(time float64) Mat4 {
+	i, t := m.segmentAt(time)
+	if t == 0 || m.keyframes[i].transform == m.keyframes[i+1].transform {
+		return m.keyframes[i].transform
+	}
+	return m.keyframes[i].decomposed.interpolate(m.keyframes[i+1].decomposed, t).toMat4()
+}
+
+func (m *motion) inverseAt(time float64) Mat4 {
+	i, t := m.segmentAt(time)
+	if t == 0 || m.keyframes[i].transform == m.keyframes[i+1].transform {
+		return m.keyframes[i].inverse
+	}
+	transform := m.transformAt(time)
+	return transform.Inverse()
+}
+
+// Box containing the local bounds during the whole motion
+func (m *motion) bounds(local BoundingBox) BoundingBox {
+	bounds := NewEmptyBoundingBox()
+	for i, k := range m.keyframes {
+		transform := k.transform.ToMatrix()
+		bounds = bounds.Merge(local.Transform(transform))
+		if i == 0 {
+			continue
+		}
+
+		// translation and scaling are linear, so the shape stays within the boxes of the
+		// keyframes. But rotating corners go along arcs, which may stick out of them, so
+		// the sphere around the whole box is swept along the segment instead
+		previous := m.keyframes[i-1]
+		if math.Abs(previous.decomposed.rotation.dot(k.decomposed.rotation)) < 1-EPSILON {
+			radius := math.Max(sweptRadius(local, previous.decomposed.scale), sweptRadius(local, k.decomposed.scale))
+			for _, center := range []Vec3{previous.decomposed.translation, k.decomposed.translation} {
+				bounds = bounds.AddPoint(NewPoint3(center.x-radius, center.y-radius, center.z-radius))
+				bounds = bounds.AddPoint(NewPoint3(center.x+radius, center.y+radius, center.z+radius))
+			}
+		}
+	}
+	return bounds
+}
+
+// The farthest distance from the origin of the scaled box
+func sweptRadius(local BoundingBox, scale Vec3) float64 {
+	x := math.Max(math.Abs(local.min.x), math.Abs(local.max.x)) * math.Abs(scale.x)
+	y := math.Max(math.Abs(local.min.y), math.Abs(local.max.y)) * math.Abs(scale.y)
+	z := math.Max(math.Abs(local.min.z), math.Abs(local.max.z)) * math.Abs(scale.z)
+	return math.Sqrt(x*x + y*y + z*z)
+}
@@ -0,0 +1,112 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecomposedTransformIsComposedBack(t *testing.T) {
+	transforms := []*Matrix{
+		NewIdentityMatrix(4),
+		NewTranslationMatrix(1, -2, 3),
+		NewRotationXMatrix(math.Pi / 3),
+		NewRotationYMatrix(math.Pi),
+		NewRotationZMatrix(-3 * math.Pi / 4),
+		NewScalingMatrix(-1, 2, 3),
+		NewScalingMatrix(0.5, 2, 4).RotateY(1).RotateX(-2).Translate(5, 0, 1),
+	}
+
+	for _, transform := range transforms {
+		m := transform.ToMat4()
+		composed := decomposeTransform(&m).toMat4()
+
+		require.True(t, m.Equal(&composed), "%v", m)
+	}
+}
+
+func TestInterpolatingDecomposedTransforms(t *testing.T) {
+	start, end := NewIdentityMat4(), NewScalingMatrix(3, 1, 1).RotateZ(math.Pi/2).Translate(2, 4, 0).ToMat4()
+	a, b := decomposeTransform(&start), decomposeTransform(&end)
+
+	half := a.interpolate(b, 0.5).toMat4()
+
+	expect := NewScalingMatrix(2, 1, 1).RotateZ(math.Pi/4).Translate(1, 2, 0).ToMat4()
+	require.True(t, expect.Equal(&half), "%v", half)
+}
+
+func TestSlerpGoesAlongTheShortestArc(t *testing.T) {
+	a := NewRotationYMatrix(0.1).ToMat4()
+	b := NewRotationYMatrix(2*math.Pi - 0.1).ToMat4()
+	qa, qb := quaternionFromRotation(&a), quaternionFromRotation(&b)
+
+	half := decomposedTransform{rotation: qa.slerp(qb, 0.5), scale: NewVec3(1, 1, 1)}.toMat4()
+
+	identity := NewIdentityMat4()
+	require.True(t, identity.Equal(&half), "%v", half)
+}
+
+func TestMotionIsInterpolatedBetweenKeyframes(t *testing.T) {
+	m := newMotion([]Keyframe{
+		NewKeyframe(2, NewTranslationMatrix(0, 10, 0)),
+		NewKeyframe(0, NewTranslationMatrix(0, 0, 0)),
+		NewKeyframe(1, NewTranslationMatrix(4, 0, 0)),
+	})
+
+	tests := []struct {
+		time   float64
+		expect Point3
+	}{
+		{-1, NewPoint3(0, 0, 0)},
+		{0.5, NewPoint3(2, 0, 0)},
+		{1, NewPoint3(4, 0, 0)},
+		{1.5, NewPoint3(2, 5, 0)},
+		{3, NewPoint3(0, 10, 0)},
+	}
+
+	for _, test := range tests {
+		transform := m.transformAt(test.time)
+		inverse := m.inverseAt(test.time)
+		p := transform.MulPoint(NewPoint3(0, 0, 0))
+
+		require.True(t, p.Equal(test.expect), "time %v: %v", test.time, p)
+		require.True(t, inverse.MulPoint(p).Equal(NewPoint3(0, 0, 0)), "time %v", test.time)
+	}
+}
+
+func TestKeyframesWithShearingAreExactAtTheirTime(t *testing.T) {
+	sheared := NewShearingMatrix(1, 0, 0, 0, 0, 0)
+	m := newMotion([]Keyframe{NewKeyframe(0, sheared), NewKeyframe(1, NewIdentityMatrix(4))})
+
+	transform := m.transformAt(0)
+	expect := sheared.ToMat4()
+	require.True(t, expect.Equal(&transform))
+}
+
+func TestMotionBoundsContainTheShapeDuringTheWholeMotion(t *testing.T) {
+	local := NewBoundingBox(NewPoint3(-1, -0.1, -0.1), NewPoint3(1, 0.1, 0.1))
+	m := newMotion([]Keyframe{
+		NewKeyframe(0, NewTranslationMatrix(0, 0, 0)),
+		NewKeyframe(1, NewRotationZMatrix(math.Pi/2).Translate(5, 0, 0)),
+	})
+	bounds := m.bounds(local)
+
+	for time := 0.; time <= 1; time += 0.05 {
+		transform := m.transformAt(time)
+		for _, corner := range []Point3{local.min, local.max, NewPoint3(1, 0.1, -0.1), NewPoint3(-1, -0.1, 0.1)} {
+			p := transform.MulPoint(corner)
+			require.True(t, bounds.ContainsPoint(p), "time %v: %v", time, p)
+		}
+	}
+}
+
+func TestMotionBoundsOfTranslationAreTheBoxesOfTheKeyframes(t *testing.T) {
+	local := NewBoundingBox(NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1))
+	m := newMotion([]Keyframe{
+		NewKeyframe(0, NewTranslationMatrix(0, 0, 0)),
+		NewKeyframe(1, NewTranslationMatrix(5, 0, 0)),
+	})
+
+	require.Equal(t, NewBoundingBox(NewPoint3(-1, -1, -1), NewPoint3(6, 1, 1)), m.bounds(local))
+}
@@ -0,0 +1,100 @@
+package ray_tracer
+
+import "math"
+
+// Number of noise layers summed by Turbulence, each next one has twice the frequency
+// and half the amplitude of the previous one
+const TURBULENCE_OCTAVES = 6
+
+// Ken Perlin's reference permutation of 0..255
+var perlinPermutation = [256]int{
+	151, 160, 137, 91, 90, 15, 131, 13, 201, 95, 96, 53, 194, 233, 7, 225,
+	140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23, 190, 6, 148,
+	247, 120, 234, 75, 0, 26, 197, 62, 94, 252, 219, 203, 117, 35, 11, 32,
+	57, 177, 33, 88, 237, 149, 56, 87, 174, 20, 125, 136, 171, 168, 68, 175,
+	74, 165, 71, 134, 139, 48, 27, 166, 77, 146, 158, 231, 83, 111, 229, 122,
+	60, 211, 133, 230, 220, 105, 92, 41, 55, 46, 245, 40, 244, 102, 143, 54,
+	65, 25, 63, 161, 1, 216, 80, 73, 209, 76, 132, 187, 208, 89, 18, 169,
+	200, 196, 135, 130, 116, 188, 159, 86, 164, 100, 109, 198, 173, 186, 3, 64,
+	52, 217, 226, 250, 124, 123, 5, 202, 38, 147, 118, 126, 255, 82, 85, 212,
+	207, 206, 59, 227, 47, 16, 58, 17, 182, 189, 28, 42, 223, 183, 170, 213,
+	119, 248, 152, 2, 44, 154, 163, 70, 221, 153, 101, 155, 167, 43, 172, 9,
+	129, 22, 39, 253, 19, 98, 108, 110, 79, 113, 224, 232, 178, 185, 112, 104,
+	218, 246, 97, 228, 251, 34, 242, 193, 238, 210, 144, 12, 191, 179, 162, 241,
+	81, 51, 145, 235, 249, 14, 239, 107, 49, 192, 214, 31, 181, 199, 106, 157,
+	184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254, 138, 236, 205, 93,
+	222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180,
+}
+
+func perlinHash(i int) int {
+	return perlinPermutation[i&255]
+}
+
+// 6t^5 - 15t^4 + 10t^3, its first and second derivatives are 0 at 0 and 1,
+// so the noise is smooth across the lattice cells
+func perlinFade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// Dot product of the distance vector with one of 12 gradient directions chosen by the hash
+func perlinGradient(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u, v := y, z
+	if h < 8 {
+		u = x
+	}
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// Ken Perlin's improved gradient noise. It's smooth, repeats every 256 units
+// and is 0 in all the integer points. Result is roughly in [-1, 1]
+func PerlinNoise(p Point3) float64 {
+	fx, fy, fz := math.Floor(p.x), math.Floor(p.y), math.Floor(p.z)
+	// cell of the lattice the point is in
+	X, Y, Z := int(fx)&255, int(fy)&255, int(fz)&255
+	// position of the point inside the cell
+	x, y, z := p.x-fx, p.y-fy, p.z-fz
+	u, v, w := perlinFade(x), perlinFade(y), perlinFade(z)
+
+	// hashes of the 8 corners of the cell
+	a := perlinHash(X) + Y
+	aa, ab := perlinHash(a)+Z, perlinHash(a+1)+Z
+	b := perlinHash(X+1) + Y
+	ba, bb := perlinHash(b)+Z, perlinHash(b+1)+Z
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, perlinGradient(perlinHash(aa), x, y, z), perlinGradient(perlinHash(ba), x-1, y, z)),
+			lerp(u, perlinGradient(perlinHash(ab), x, y-1, z), perlinGradient(perlinHash(bb), x-1, y-1, z))),
+		lerp(v,
+			lerp(u, perlinGradient(perlinHash(aa+1), x, y, z-1), perlinGradient(perlinHash(ba+1), x-1, y, z-1)),
+			lerp(u, perlinGradient(perlinHash(ab+1), x, y-1, z-1), perlinGradient(perlinHash(bb+1), x-1, y-1, z-1))))
+}
+
+// Fractal sum of the absolute noise values. Every octave has twice the frequency
+// and half the amplitude of the previous one. Result is nonnegative
+func Turbulence(p Point3, octaves int) float64 {
+	sum := 0.
+	frequency := 1.
+	for i := 0; i < octaves; i++ {
+		sum += math.Abs(PerlinNoise(NewPoint3(p.x*frequency, p.y*frequency, p.z*frequency))) / frequency
+		frequency *= 2
+	}
+	return sum
+}
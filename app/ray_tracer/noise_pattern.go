@@ -0,0 +1,87 @@
+package ray_tracer
+
+import "math"
+
+// Smoothly blends two colors depending on Perlin noise
+type NoisePattern struct {
+	pattern
+	patternPair
+}
+
+func NewNoisePattern(a, b Color) *NoisePattern {
+	return NewNestedNoisePattern(NewSolidPattern(a), NewSolidPattern(b))
+}
+
+func NewNestedNoisePattern(a, b Pattern) *NoisePattern {
+	return &NoisePattern{pattern: newPattern(), patternPair: patternPair{a, b}}
+}
+
+func (p *NoisePattern) localColorAt(point Point3) Color {
+	return p.mix((PerlinNoise(point)+1)/2, point)
+}
+
+// Jitters the point with noise before passing it to the nested pattern, so
+// straight lines of the pattern become wavy
+type PerturbedPattern struct {
+	pattern
+	nested Pattern
+	// maximum distance the point is moved by
+	scale float64
+}
+
+func NewPerturbedPattern(nested Pattern, scale float64) *PerturbedPattern {
+	return &PerturbedPattern{pattern: newPattern(), nested: nested, scale: scale}
+}
+
+func (p *PerturbedPattern) localColorAt(point Point3) Color {
+	// noise is sampled far away from the point for the other axes, otherwise
+	// the point would be moved only along the diagonal
+	jitter := NewVec3(
+		PerlinNoise(point),
+		PerlinNoise(NewPoint3(point.x+31.4, point.y+15.9, point.z+26.5)),
+		PerlinNoise(NewPoint3(point.x-35.8, point.y-97.9, point.z-32.3)),
+	)
+	return PatternAt(p.nested, point.Add(jitter.Mul(p.scale)))
+}
+
+// Veins along YZ plane bent by turbulence. The bigger the turbulence,
+// the more distorted the veins are
+type MarblePattern struct {
+	pattern
+	patternPair
+	turbulence float64
+}
+
+func NewMarblePattern(a, b Color, turbulence float64) *MarblePattern {
+	return NewNestedMarblePattern(NewSolidPattern(a), NewSolidPattern(b), turbulence)
+}
+
+func NewNestedMarblePattern(a, b Pattern, turbulence float64) *MarblePattern {
+	return &MarblePattern{pattern: newPattern(), patternPair: patternPair{a, b}, turbulence: turbulence}
+}
+
+func (p *MarblePattern) localColorAt(point Point3) Color {
+	veins := math.Sin((point.x + p.turbulence*Turbulence(point, TURBULENCE_OCTAVES)) * math.Pi)
+	return p.mix((veins+1)/2, point)
+}
+
+// Growth rings of unit width around Y axis distorted by turbulence. Each ring
+// goes from the first color to the second one
+type WoodPattern struct {
+	pattern
+	patternPair
+	turbulence float64
+}
+
+func NewWoodPattern(a, b Color, turbulence float64) *WoodPattern {
+	return NewNestedWoodPattern(NewSolidPattern(a), NewSolidPattern(b), turbulence)
+}
+
+func NewNestedWoodPattern(a, b Pattern, turbulence float64) *WoodPattern {
+	return &WoodPattern{pattern: newPattern(), patternPair: patternPair{a, b}, turbulence: turbulence}
+}
+
+func (p *WoodPattern) localColorAt(point Point3) Color {
+	distance := math.Sqrt(point.x*point.x+point.z*point.z) + p.turbulence*Turbulence(point, TURBULENCE_OCTAVES)
+	return p.mix(distance-math.Floor(distance), point)
+}
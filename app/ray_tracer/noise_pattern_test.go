@@ -0,0 +1,79 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoisePatternIsHalfwayBetweenColorsInIntegerPoints(t *testing.T) {
+	p := NewNoisePattern(WHITE, BLACK)
+
+	require.True(t, PatternAt(p, NewPoint3(1, 2, 3)).Equal(NewColor(0.5, 0.5, 0.5)))
+}
+
+func TestNoisePatternVariesBetweenIntegerPoints(t *testing.T) {
+	p := NewNoisePattern(WHITE, BLACK)
+
+	c := PatternAt(p, NewPoint3(0.3, 0.6, 0.2))
+
+	require.False(t, c.Equal(NewColor(0.5, 0.5, 0.5)))
+	require.True(t, c.r >= 0 && c.r <= 1)
+}
+
+func TestPerturbedPatternWithZeroScaleIsTheNestedPattern(t *testing.T) {
+	stripes := NewStripePattern(WHITE, BLACK)
+	p := NewPerturbedPattern(stripes, 0)
+
+	for x := -2.; x < 2; x += 0.1 {
+		point := NewPoint3(x, 0.3, 0.7)
+		require.Equal(t, PatternAt(stripes, point), PatternAt(p, point))
+	}
+}
+
+func TestPerturbedPatternMovesStripeBorders(t *testing.T) {
+	stripes := NewStripePattern(WHITE, BLACK)
+	p := NewPerturbedPattern(stripes, 0.5)
+
+	differences := 0
+	for x := -2.; x < 2; x += 0.05 {
+		point := NewPoint3(x, 0.3, 0.7)
+		if PatternAt(stripes, point) != PatternAt(p, point) {
+			differences++
+		}
+	}
+
+	require.Greater(t, differences, 0)
+}
+
+func TestMarbleWithoutTurbulenceIsSineStripes(t *testing.T) {
+	p := NewMarblePattern(WHITE, BLACK, 0)
+
+	require.True(t, PatternAt(p, NewPoint3(0, 5, 5)).Equal(NewColor(0.5, 0.5, 0.5)))
+	require.True(t, PatternAt(p, NewPoint3(0.5, 0, 0)).Equal(BLACK))
+	require.True(t, PatternAt(p, NewPoint3(1.5, 0, 0)).Equal(WHITE))
+}
+
+func TestMarbleTurbulenceDistortsTheVeins(t *testing.T) {
+	straight := NewMarblePattern(WHITE, BLACK, 0)
+	distorted := NewMarblePattern(WHITE, BLACK, 2)
+	point := NewPoint3(0.3, 0.2, 0.1)
+
+	require.False(t, PatternAt(straight, point).Equal(PatternAt(distorted, point)))
+}
+
+func TestWoodWithoutTurbulenceHasRingsAroundYAxis(t *testing.T) {
+	p := NewWoodPattern(WHITE, BLACK, 0)
+
+	require.True(t, PatternAt(p, NewPoint3(0, 7, 0)).Equal(WHITE))
+	require.True(t, PatternAt(p, NewPoint3(0.25, 0, 0)).Equal(NewColor(0.75, 0.75, 0.75)))
+	require.True(t, PatternAt(p, NewPoint3(0, -3, 1.75)).Equal(NewColor(0.25, 0.25, 0.25)))
+}
+
+func TestNestedWoodUsesNestedPatterns(t *testing.T) {
+	stripes := NewStripePattern(RED, GREEN)
+	p := NewNestedWoodPattern(stripes, NewSolidPattern(BLACK), 0)
+
+	require.True(t, PatternAt(p, NewPoint3(0, 0, 0)).Equal(RED))
+	require.True(t, PatternAt(p, NewPoint3(-1, 0, 0)).Equal(GREEN))
+}
@@ -0,0 +1,61 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerlinPermutationContainsEveryByteOnce(t *testing.T) {
+	seen := [256]bool{}
+	for _, v := range perlinPermutation {
+		require.False(t, seen[v], "%d is repeated", v)
+		seen[v] = true
+	}
+}
+
+func TestPerlinNoiseIsZeroInIntegerPoints(t *testing.T) {
+	for _, p := range []Point3{NewPoint3(0, 0, 0), NewPoint3(1, 2, 3), NewPoint3(-5, 17, -300)} {
+		require.EqualValues(t, 0, PerlinNoise(p), p)
+	}
+}
+
+func TestPerlinNoiseIsBoundedAndNotConstant(t *testing.T) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for x := -3.; x < 3; x += 0.13 {
+		for y := -3.; y < 3; y += 0.17 {
+			for z := -3.; z < 3; z += 0.19 {
+				n := PerlinNoise(NewPoint3(x, y, z))
+				min, max = math.Min(min, n), math.Max(max, n)
+			}
+		}
+	}
+
+	require.GreaterOrEqual(t, min, -1.)
+	require.LessOrEqual(t, max, 1.)
+	require.Less(t, min, -0.3)
+	require.Greater(t, max, 0.3)
+}
+
+func TestPerlinNoiseIsSmooth(t *testing.T) {
+	p := NewPoint3(1.3, -2.7, 0.42)
+	step := NewVec3(1e-4, 1e-4, 1e-4)
+
+	require.InDelta(t, PerlinNoise(p), PerlinNoise(p.Add(step)), 1e-3)
+}
+
+func TestPerlinNoiseRepeatsEvery256Units(t *testing.T) {
+	p := NewPoint3(1.3, -2.7, 0.42)
+
+	require.InDelta(t, PerlinNoise(p), PerlinNoise(NewPoint3(p.x+256, p.y, p.z-256)), 1e-9)
+}
+
+func TestTurbulenceIsSumOfAbsoluteNoiseOctaves(t *testing.T) {
+	p := NewPoint3(0.3, 0.7, -1.1)
+
+	require.Equal(t, math.Abs(PerlinNoise(p)), Turbulence(p, 1))
+	expect := math.Abs(PerlinNoise(p)) + math.Abs(PerlinNoise(NewPoint3(0.6, 1.4, -2.2)))/2
+	require.InDelta(t, expect, Turbulence(p, 2), 1e-12)
+	require.GreaterOrEqual(t, Turbulence(p, TURBULENCE_OCTAVES), Turbulence(p, 2))
+}
@@ -0,0 +1,279 @@
+package ray_tracer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const defaultObjGroupName = ""
+
+// Geometry parsed from a Wavefront OBJ file. Only vertices (v), vertex normals (vn),
+// texture vertices (vt, the third coordinate is dropped), faces (f) and groups (g/o)
+// are supported, all the other statements are ignored.
+type ObjFile struct {
+	vertices        []Point3
+	normals         []Vec3
+	textureVertices []UV
+	// groups are kept in the order they appear in the file, faces before the first
+	// g/o statement go to the default group with an empty name
+	groupNames     []string
+	groups         map[string][]objTriangle
+	trianglesCount int
+	ignoredLines   int
+}
+
+// Triangle of a triangulated face. Shapes are made of it anew every time they're
+// requested, so the same file may be placed into many groups with different transforms
+type objTriangle struct {
+	id         string
+	p1, p2, p3 Point3
+	smooth     bool
+	n1, n2, n3 Vec3
+}
+
+func (t objTriangle) toShape() Shape {
+	if t.smooth {
+		tri := NewSmoothTriangle(t.id, NewDefaultMaterial(), t.p1, t.p2, t.p3, t.n1, t.n2, t.n3)
+		return &tri
+	}
+	tri := NewTriangle(t.id, NewDefaultMaterial(), t.p1, t.p2, t.p3)
+	return &tri
+}
+
+func toShapes(triangles []objTriangle) []Shape {
+	shapes := make([]Shape, 0, len(triangles))
+	for _, t := range triangles {
+		shapes = append(shapes, t.toShape())
+	}
+	return shapes
+}
+
+func newObjFile() *ObjFile {
+	return &ObjFile{
+		groupNames: []string{defaultObjGroupName},
+		groups:     map[string][]objTriangle{defaultObjGroupName: {}},
+	}
+}
+
+func ParseObjFile(filename string) (*ObjFile, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	obj, err := ParseObj(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return obj, nil
+}
+
+func ParseObj(r io.Reader) (*ObjFile, error) {
+	obj := newObjFile()
+	currentGroup := defaultObjGroupName
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "v":
+			var p [4]float64
+			p, err = parseObjCoordinates(fields[1:], 3, 4)
+			obj.vertices = append(obj.vertices, NewPoint3(p[0], p[1], p[2]))
+		case "vn":
+			var n [4]float64
+			n, err = parseObjCoordinates(fields[1:], 3, 3)
+			obj.normals = append(obj.normals, NewVec3(n[0], n[1], n[2]))
+		case "vt":
+			var uv [4]float64
+			uv, err = parseObjCoordinates(fields[1:], 1, 3)
+			obj.textureVertices = append(obj.textureVertices, NewUV(uv[0], uv[1]))
+		case "f":
+			var triangles []objTriangle
+			triangles, err = obj.parseFace(fields[1:])
+			obj.groups[currentGroup] = append(obj.groups[currentGroup], triangles...)
+		case "g", "o":
+			if len(fields) < 2 {
+				err = fmt.Errorf("%q statement without a name", fields[0])
+				break
+			}
+			currentGroup = strings.Join(fields[1:], " ")
+			if _, ok := obj.groups[currentGroup]; !ok {
+				obj.groupNames = append(obj.groupNames, currentGroup)
+				obj.groups[currentGroup] = []objTriangle{}
+			}
+		default:
+			obj.ignoredLines++
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Parses from minCount to maxCount (at most 4) numbers. Missing ones are left zero
+func parseObjCoordinates(fields []string, minCount, maxCount int) ([4]float64, error) {
+	coordinates := [4]float64{}
+	if len(fields) < minCount || len(fields) > maxCount {
+		return coordinates, fmt.Errorf("expected from %d to %d coordinates, got %d", minCount, maxCount, len(fields))
+	}
+
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return coordinates, fmt.Errorf("invalid coordinate %q", field)
+		}
+		coordinates[i] = value
+	}
+	return coordinates, nil
+}
+
+// Resolves 1-based (or negative, relative to the end) OBJ index into a 0-based one
+func resolveObjIndex(field string, count int) (int, error) {
+	index, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", field)
+	}
+	if index < 0 {
+		index = count + index + 1
+	}
+	if index < 1 || index > count {
+		return 0, fmt.Errorf("index %q is out of range [1, %d]", field, count)
+	}
+	return index - 1, nil
+}
+
+type objFaceVertex struct {
+	vertex  int
+	texture int
+	normal  int
+}
+
+// Face vertex may be written as "v", "v/vt", "v//vn" or "v/vt/vn"
+func (obj *ObjFile) parseFaceVertex(field string) (objFaceVertex, error) {
+	fv := objFaceVertex{vertex: -1, texture: -1, normal: -1}
+	parts := strings.Split(field, "/")
+	if len(parts) > 3 {
+		return fv, fmt.Errorf("invalid face vertex %q", field)
+	}
+
+	var err error
+	if fv.vertex, err = resolveObjIndex(parts[0], len(obj.vertices)); err != nil {
+		return fv, fmt.Errorf("vertex %w", err)
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if fv.texture, err = resolveObjIndex(parts[1], len(obj.textureVertices)); err != nil {
+			return fv, fmt.Errorf("texture vertex %w", err)
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if fv.normal, err = resolveObjIndex(parts[2], len(obj.normals)); err != nil {
+			return fv, fmt.Errorf("normal %w", err)
+		}
+	}
+	return fv, nil
+}
+
+// Polygons are triangulated with a "fan": all the triangles share the first vertex.
+// It works only for convex polygons, but that's what OBJ files usually have.
+// Triangles with the vertices on one line have no normal, so they are rejected
+func (obj *ObjFile) parseFace(fields []string) ([]objTriangle, error) {
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("face must have at least 3 vertices, got %d", len(fields))
+	}
+
+	faceVertices := make([]objFaceVertex, 0, len(fields))
+	for _, field := range fields {
+		fv, err := obj.parseFaceVertex(field)
+		if err != nil {
+			return nil, err
+		}
+		faceVertices = append(faceVertices, fv)
+	}
+
+	triangles := []objTriangle{}
+	for i := 1; i < len(faceVertices)-1; i++ {
+		a, b, c := faceVertices[0], faceVertices[i], faceVertices[i+1]
+		p1, p2, p3 := obj.vertices[a.vertex], obj.vertices[b.vertex], obj.vertices[c.vertex]
+		if p2.Sub(p1).Cross(p3.Sub(p1)).Magnitude() < EPSILON {
+			return nil, fmt.Errorf("degenerate face, vertices %s, %s and %s are on one line", fields[0], fields[i], fields[i+1])
+		}
+		obj.trianglesCount++
+		tri := objTriangle{id: fmt.Sprintf("triangle_%d", obj.trianglesCount), p1: p1, p2: p2, p3: p3}
+
+		if a.normal >= 0 && b.normal >= 0 && c.normal >= 0 {
+			tri.smooth = true
+			tri.n1, tri.n2, tri.n3 = obj.normals[a.normal], obj.normals[b.normal], obj.normals[c.normal]
+		}
+		triangles = append(triangles, tri)
+	}
+	return triangles, nil
+}
+
+func (obj *ObjFile) TrianglesCount() int {
+	return obj.trianglesCount
+}
+
+// Returns new triangles of the named group, or of the default one, if name is empty
+func (obj *ObjFile) Group(name string) []Shape {
+	return toShapes(obj.groups[name])
+}
+
+func (obj *ObjFile) GroupNames() []string {
+	return obj.groupNames
+}
+
+// New triangles from all the groups in the order they appear in the file
+func (obj *ObjFile) Triangles() []Shape {
+	all := []Shape{}
+	for _, name := range obj.groupNames {
+		all = append(all, obj.Group(name)...)
+	}
+	return all
+}
+
+// Converts the whole file into a single group of new triangles. Each named OBJ
+// group becomes a subgroup, triangles of the default group are added directly
+func (obj *ObjFile) ToGroup(id string) *Group {
+	g := NewGroup(id)
+	for _, name := range obj.groupNames {
+		if name == defaultObjGroupName {
+			for _, tri := range obj.Group(name) {
+				g.AddChild(tri)
+			}
+			continue
+		}
+
+		subgroup := NewGroup(name)
+		for _, tri := range obj.Group(name) {
+			subgroup.AddChild(tri)
+		}
+		g.AddChild(subgroup)
+	}
+	return g
+}
+
+// Places the whole mesh into the world as a single group, so it can be moved
+// and painted at once
+func (obj *ObjFile) AddToWorld(w *World, name string, transform *Matrix, material Material) {
+	g := obj.ToGroup(name)
+	g.SetTransform(transform)
+	g.SetMaterial(material)
+	w.Add(name, g)
+}
@@ -0,0 +1,264 @@
+package ray_tracer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoringUnrecognizedLines(t *testing.T) {
+	gibberish := `There was a young lady named Bright
+who traveled much faster than light.
+She set out one day
+in a relative way,
+and came back the previous night.`
+
+	obj, err := ParseObj(strings.NewReader(gibberish))
+
+	require.NoError(t, err)
+	require.Equal(t, 5, obj.ignoredLines)
+}
+
+func TestParsingVertexRecords(t *testing.T) {
+	file := `v -1 1 0
+v -1.0000 0.5000 0.0000
+v 1 0 0
+v 1 1 0`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	require.NoError(t, err)
+	require.Len(t, obj.vertices, 4)
+	require.True(t, obj.vertices[0].Equal(NewPoint3(-1, 1, 0)))
+	require.True(t, obj.vertices[1].Equal(NewPoint3(-1, 0.5, 0)))
+	require.True(t, obj.vertices[2].Equal(NewPoint3(1, 0, 0)))
+	require.True(t, obj.vertices[3].Equal(NewPoint3(1, 1, 0)))
+}
+
+func TestParsingTriangleFaces(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+f 1 2 3
+f 1 3 4`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	require.NoError(t, err)
+	g := obj.Group(defaultObjGroupName)
+	require.Len(t, g, 2)
+	t1, t2 := g[0].(*Triangle), g[1].(*Triangle)
+	require.True(t, t1.p1.Equal(obj.vertices[0]))
+	require.True(t, t1.p2.Equal(obj.vertices[1]))
+	require.True(t, t1.p3.Equal(obj.vertices[2]))
+	require.True(t, t2.p1.Equal(obj.vertices[0]))
+	require.True(t, t2.p2.Equal(obj.vertices[2]))
+	require.True(t, t2.p3.Equal(obj.vertices[3]))
+}
+
+func TestTriangulatingPolygons(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+v 0 2 0
+
+f 1 2 3 4 5`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	require.NoError(t, err)
+	g := obj.Group(defaultObjGroupName)
+	require.Len(t, g, 3)
+	for i, tri := range g {
+		tri := tri.(*Triangle)
+		require.True(t, tri.p1.Equal(obj.vertices[0]))
+		require.True(t, tri.p2.Equal(obj.vertices[i+1]))
+		require.True(t, tri.p3.Equal(obj.vertices[i+2]))
+	}
+}
+
+func TestTrianglesInGroups(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+g FirstGroup
+f 1 2 3
+o SecondGroup
+f 1 3 4`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "FirstGroup", "SecondGroup"}, obj.GroupNames())
+	require.Len(t, obj.Group("FirstGroup"), 1)
+	require.Len(t, obj.Group("SecondGroup"), 1)
+	require.Len(t, obj.Triangles(), 2)
+	require.Equal(t, 2, obj.TrianglesCount())
+}
+
+func TestParsingVertexNormalsAndTextureVertices(t *testing.T) {
+	file := `vn 0 0 1
+vn 0.707 0 -0.707
+vn 1 2 3
+vt 0.5 0.25`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	require.NoError(t, err)
+	require.Len(t, obj.normals, 3)
+	require.True(t, obj.normals[0].Equal(NewVec3(0, 0, 1)))
+	require.True(t, obj.normals[1].Equal(NewVec3(0.707, 0, -0.707)))
+	require.True(t, obj.normals[2].Equal(NewVec3(1, 2, 3)))
+	require.Len(t, obj.textureVertices, 1)
+	require.Equal(t, NewUV(0.5, 0.25), obj.textureVertices[0])
+}
+
+func TestFacesWithNormalsProduceSmoothTriangles(t *testing.T) {
+	file := `v 0 1 0
+v -1 0 0
+v 1 0 0
+vt 0 0
+vn -1 0 0
+vn 1 0 0
+vn 0 1 0
+f 1//3 2//1 3//2
+f 1/1/3 2/1/1 3/1/2
+f -3 -2 -1`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	require.NoError(t, err)
+	g := obj.Group(defaultObjGroupName)
+	require.Len(t, g, 3)
+	t1, t2 := g[0].(*SmoothTriangle), g[1].(*SmoothTriangle)
+	for _, tri := range []*SmoothTriangle{t1, t2} {
+		require.True(t, tri.p1.Equal(obj.vertices[0]))
+		require.True(t, tri.p2.Equal(obj.vertices[1]))
+		require.True(t, tri.p3.Equal(obj.vertices[2]))
+		require.True(t, tri.n1.Equal(obj.normals[2]))
+		require.True(t, tri.n2.Equal(obj.normals[0]))
+		require.True(t, tri.n3.Equal(obj.normals[1]))
+	}
+	t3 := g[2].(*Triangle)
+	require.True(t, t3.p1.Equal(obj.vertices[0]))
+	require.True(t, t3.p3.Equal(obj.vertices[2]))
+}
+
+func TestMalformedLinesAreReportedWithLineNumbers(t *testing.T) {
+	testCases := []struct {
+		file string
+		err  string
+	}{
+		{"v 1 2 3\nv 1 two 3", "line 2: invalid coordinate \"two\""},
+		{"v 1 2", "line 1: expected from 3 to 4 coordinates, got 2"},
+		{"v 1 2 3\nv 1 2 3\n\nf 1 2", "line 4: face must have at least 3 vertices, got 2"},
+		{"v 1 2 3\nv 1 2 3\nv 1 2 3\nf 1 2 4", "line 4: vertex index \"4\" is out of range [1, 3]"},
+		{"v 1 2 3\nv 1 2 3\nv 1 2 3\nf 1//1 2 3", "line 4: normal index \"1\" is out of range [1, 0]"},
+		{"g", "line 1: \"g\" statement without a name"},
+		{"v 0 0 0\nv 1 1 1\nv 2 2 2\nv 0 1 0\nf 1 2 3 4", "line 5: degenerate face, vertices 1, 2 and 3 are on one line"},
+	}
+
+	for _, tc := range testCases {
+		require.NotPanics(t, func() {
+			_, err := ParseObj(strings.NewReader(tc.file))
+
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestParsingObjFileFromDisk(t *testing.T) {
+	filename := "test_triangle.obj"
+	err := os.WriteFile(filename, []byte("v 0 1 0\nv -1 0 0\nv 1 0 0\nf 1 2 3\n"), 0644)
+	require.NoError(t, err)
+	defer cleanup(filename)
+
+	obj, err := ParseObjFile(filename)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, obj.TrianglesCount())
+}
+
+func TestParsingMissingObjFileFails(t *testing.T) {
+	_, err := ParseObjFile("no_such_file.obj")
+
+	require.Error(t, err)
+}
+
+func TestAddingObjTrianglesToWorld(t *testing.T) {
+	file := `v 0 1 0
+v -1 0 0
+v 1 0 0
+f 1 2 3`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.NoError(t, err)
+	w := NewWorld()
+	m := NewDefaultMaterial()
+	m.color = RED
+
+	obj.AddToWorld(w, "mesh", NewTranslationMatrix(0, 0, 5), m)
+
+	require.Equal(t, 1, w.Len())
+	tri := w.Object("mesh").(*Group).Children()[0].(*Triangle)
+	require.Equal(t, RED, tri.material.color)
+	r := NewRay(NewPoint3(0, 0.5, 0), NewVec3(0, 0, 1))
+	xs := w.IntersectWith(&r)
+	require.Len(t, xs, 1)
+	require.InDelta(t, 5, xs[0].time, EPSILON)
+}
+
+func TestConvertingObjFileToGroup(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+f 1 2 4
+g FirstGroup
+f 1 2 3
+g SecondGroup
+f 1 3 4`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.NoError(t, err)
+
+	g := obj.ToGroup("mesh")
+
+	require.Len(t, g.Children(), 3)
+	require.Equal(t, "triangle_1", g.Children()[0].Id())
+	first := g.Children()[1].(*Group)
+	require.Equal(t, "FirstGroup", first.Id())
+	require.Len(t, first.Children(), 1)
+	require.Equal(t, "triangle_2", first.Children()[0].Id())
+	second := g.Children()[2].(*Group)
+	require.Equal(t, "SecondGroup", second.Id())
+	require.Len(t, second.Children(), 1)
+	require.True(t, second.Children()[0].(*Triangle).p3.Equal(obj.vertices[3]))
+}
+
+func TestEachObjGroupHasItsOwnTriangles(t *testing.T) {
+	file := `v 0 1 0
+v -1 0 0
+v 1 0 0
+f 1 2 3`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.NoError(t, err)
+	w := NewWorld()
+	m := NewDefaultMaterial()
+	m.color = RED
+
+	obj.AddToWorld(w, "red", NewIdentityMatrix(4), m)
+	obj.AddToWorld(w, "default", NewTranslationMatrix(0, 0, 5), NewDefaultMaterial())
+
+	red := w.Object("red").(*Group).Children()[0]
+	other := w.Object("default").(*Group).Children()[0]
+	require.NotSame(t, red, other)
+	require.Same(t, w.Object("red"), red.Parent())
+	require.Equal(t, RED, red.Material().color)
+	require.Same(t, w.Object("default"), other.Parent())
+	require.Equal(t, WHITE, other.Material().color)
+}
@@ -0,0 +1,185 @@
+package ray_tracer
+
+import "math"
+
+// Algorithm calculating the color seen along a camera ray
+type Integrator int
+
+const (
+	// Whitted ray tracing: direct Phong lighting, perfect reflections and refractions and
+	// the constant ambient term instead of the light coming from the other objects
+	INTEGRATOR_WHITTED Integrator = iota
+	// Monte Carlo path tracing: light bouncing off diffuse and glossy surfaces is traced
+	// too, so there is indirect lighting and color bleeding. The ambient term is ignored.
+	// It's noisy, unless there are many samples per pixel
+	INTEGRATOR_PATH_TRACING
+)
+
+// Paths shorter than this are never terminated by Russian roulette
+const RUSSIAN_ROULETTE_DEPTH = 3
+
+// One random path starting with the ray. At every hit the lights are sampled directly
+// (next-event estimation), then the path continues in the direction chosen with the
+// probability proportional to the material's response. maxBounces limits the length of the path
+func (w *World) pathTrace(ray Ray, maxBounces int, rng *pixelRandom) Color {
+	radiance, throughput := BLACK, WHITE
+	lights := w.Lights()
+
+	for bounce := 0; ; bounce++ {
+		xs := w.IntersectWith(&ray)
+		hit, ok := Hit(xs)
+		if !ok {
+			return radiance
+		}
+		comps := PrepareIntersectionComputations(hit, ray, xs)
+
+		// lights are points, bounced rays can't hit them, so it's the only way the light gets in
+		radiance = radiance.Add(throughput.MultHadamar(directLighting(w, lights, &comps)))
+		if bounce >= maxBounces {
+			return radiance
+		}
+
+		next, weight, ok := scatter(&comps, rng)
+		if !ok {
+			return radiance
+		}
+		ray, throughput = next, throughput.MultHadamar(weight)
+
+		// dim paths are terminated randomly, the survivors are brightened to make up for it
+		if bounce >= RUSSIAN_ROULETTE_DEPTH {
+			survival := math.Min(0.95, maxComponent(throughput))
+			if rng.Float64() >= survival {
+				return radiance
+			}
+			throughput = throughput.MultScalar(1 / survival)
+		}
+	}
+}
+
+// Diffuse and specular light coming directly from the lights, the same as in ShadeHit
+func directLighting(w *World, lights []Light, comps *IntersectionComputations) Color {
+	material := comps.intersectionObject.Material()
+	material.ambient = 0
+
+	sum := BLACK
+	for _, light := range lights {
+		lightIntensity := lightIntensityAtTime(w, light, comps.overPoint, comps.rayTime)
+		if lightIntensity == 0 {
+			continue
+		}
+		sum = sum.Add(calcPartialLighting(material, comps.intersectionObject, light, comps.overPoint,
+			comps.eyev, comps.objectNormalv, lightIntensity, comps.rayTime))
+	}
+	return sum
+}
+
+// Chooses the direction the path continues in: diffuse, glossy, mirror reflection or refraction.
+// The choice is random with the probabilities proportional to how much each of them reflects,
+// the factor is divided by the probability, so on average the light of all of them is gathered.
+// Returns the ray and the factor the light coming along it is multiplied by, false if the path
+// ends here
+func scatter(comps *IntersectionComputations, rng *pixelRandom) (Ray, Color, bool) {
+	material := comps.intersectionObject.Material()
+	diffuse := surfaceColor(material, comps.intersectionObject, comps.overPoint, comps.rayTime).MultScalar(material.diffuse)
+
+	// split between reflection and refraction like in ShadeHit
+	reflective, transparency := material.reflective, material.transparency
+	if reflective > 0 && transparency > 0 {
+		reflectance := Schlick(comps)
+		reflective, transparency = reflective*reflectance, transparency*(1-reflectance)
+	}
+
+	weights := [4]float64{maxComponent(diffuse), material.specular, reflective, transparency}
+	total := 0.
+	for _, weight := range weights {
+		total += weight
+	}
+	if total <= 0 {
+		return Ray{}, BLACK, false
+	}
+
+	// rounding errors may leave the choice past all the weights, then it's the last lobe
+	lobe, choice := 0, rng.Float64()*total
+	for i, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		lobe = i
+		if choice < weight {
+			break
+		}
+		choice -= weight
+	}
+	// the lobe is chosen with the probability weight / total, so the weight itself is
+	// cancelled out, its color and directional falloff are left multiplied by total
+	scale := total / weights[lobe]
+
+	normal := comps.objectNormalv
+	newRay := func(origin Point3, direction Vec3) Ray {
+		return NewRayAtTime(origin, direction, comps.rayTime)
+	}
+
+	switch lobe {
+	case 0:
+		// cosine weighted sampling cancels out the cosine of Lambert's law
+		direction := cosineHemisphereSample(normal, rng.Float64(), rng.Float64())
+		return newRay(comps.overPoint, direction), diffuse.MultScalar(scale), true
+
+	case 1:
+		// the lobe of the specular highlight around the mirror direction (normalized Phong)
+		direction := phongLobeSample(comps.reflectv, material.shininess, rng.Float64(), rng.Float64())
+		cos := direction.Dot(normal)
+		if cos <= 0 {
+			return Ray{}, BLACK, false
+		}
+		factor := (material.shininess + 2) / (material.shininess + 1) * cos
+		return newRay(comps.overPoint, direction), WHITE.MultScalar(factor * total), true
+
+	case 2:
+		return newRay(comps.overPoint, comps.reflectv), WHITE.MultScalar(total), true
+
+	default:
+		direction, ok := refractedDirection(comps)
+		if !ok {
+			// total internal reflection
+			return newRay(comps.overPoint, comps.reflectv), WHITE.MultScalar(total), true
+		}
+		return newRay(comps.underPoint, direction), WHITE.MultScalar(total), true
+	}
+}
+
+func maxComponent(c Color) float64 {
+	return math.Max(c.r, math.Max(c.g, c.b))
+}
+
+// Two unit vectors, which are perpendicular to each other and to the unit vector n
+// (Duff et al., "Building an Orthonormal Basis, Revisited")
+func orthonormalBasis(n Vec3) (Vec3, Vec3) {
+	sign := math.Copysign(1, n.z)
+	a := -1 / (sign + n.z)
+	b := n.x * n.y * a
+	return NewVec3(1+sign*n.x*n.x*a, sign*b, -sign*n.x), NewVec3(b, sign+n.y*n.y*a, -n.y)
+}
+
+// Direction in the hemisphere around the normal, the probability is proportional to
+// the cosine of the angle with it. u and v are random numbers in [0, 1)
+func cosineHemisphereSample(normal Vec3, u, v float64) Vec3 {
+	// points uniformly distributed on the disk projected up onto the hemisphere
+	x, y := concentricDiskSample(u, v)
+	z := math.Sqrt(math.Max(0, 1-x*x-y*y))
+
+	tangent, bitangent := orthonormalBasis(normal)
+	return tangent.Mul(x).Add(bitangent.Mul(y)).Add(normal.Mul(z))
+}
+
+// Direction around the axis, the probability is proportional to cos^shininess of the
+// angle with it. u and v are random numbers in [0, 1)
+func phongLobeSample(axis Vec3, shininess, u, v float64) Vec3 {
+	axis = axis.Normalize()
+	cos := math.Pow(u, 1/(shininess+1))
+	sin := math.Sqrt(math.Max(0, 1-cos*cos))
+	phi := 2 * math.Pi * v
+
+	tangent, bitangent := orthonormalBasis(axis)
+	return tangent.Mul(sin * math.Cos(phi)).Add(bitangent.Mul(sin * math.Sin(phi))).Add(axis.Mul(cos))
+}
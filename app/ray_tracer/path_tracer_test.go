@@ -0,0 +1,227 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathTracedRayMissingEverythingIsBlack(t *testing.T) {
+	w := NewDefaultWorld()
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 1, 0))
+	rng := newPixelRandom(0, 0, 0)
+
+	require.Equal(t, BLACK, w.pathTrace(r, MAX_REFLECTION_DEPTH, &rng))
+}
+
+func TestPathWithoutBouncesIsDirectLightingWithoutAmbient(t *testing.T) {
+	w := NewDefaultWorld()
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	rng := newPixelRandom(0, 0, 0)
+
+	s := w.Object("s1").(*Sphere)
+	m := s.Material()
+	ambient := m.color.MultScalar(m.ambient)
+	whitted := w.ColorAtIntersection(r, 0)
+
+	require.True(t, whitted.Sub(ambient).Equal(w.pathTrace(r, 0, &rng)))
+}
+
+// White floor with a red wall on the left, lit from above
+func createColorBleedingWorld() *World {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(0, 10, 0), WHITE))
+
+	floor := NewPlane("floor", NewDefaultMaterial())
+	w.Add("floor", &floor)
+
+	red := NewDefaultMaterial()
+	red.color = RED
+	wall := NewPlane("wall", red)
+	wall.SetTransform(NewRotationZMatrix(math.Pi/2).Translate(-1, 0, 0))
+	w.Add("wall", &wall)
+	return w
+}
+
+func TestPathTracingBleedsColorOfTheWallOntoTheFloor(t *testing.T) {
+	w := createColorBleedingWorld()
+	r := NewRay(NewPoint3(-0.5, 1, -5), NewVec3(0, -1, 5).Normalize())
+
+	whitted := w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH)
+	require.InDelta(t, whitted.r, whitted.g, EPSILON)
+
+	rng := newPixelRandom(1, 0, 0)
+	sum := BLACK
+	const paths = 500
+	for i := 0; i < paths; i++ {
+		sum = sum.Add(w.pathTrace(r, MAX_REFLECTION_DEPTH, &rng))
+	}
+	average := sum.MultScalar(1. / paths)
+
+	require.Greater(t, average.r, average.g+0.05)
+	require.InDelta(t, average.g, average.b, EPSILON)
+}
+
+func TestScatteringFromBlackMatteSurfaceEndsThePath(t *testing.T) {
+	m := NewDefaultMaterial()
+	m.color = BLACK
+	m.specular = 0
+	s := NewDefaultSphere()
+	s.SetMaterial(m)
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	i := NewIntersection(4, &s)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+	rng := newPixelRandom(0, 0, 0)
+
+	_, _, ok := scatter(&comps, &rng)
+
+	require.False(t, ok)
+}
+
+func TestScatteringFromMirrorGoesInTheReflectedDirection(t *testing.T) {
+	m := NewDefaultMaterial()
+	m.diffuse, m.specular, m.reflective = 0, 0, 1
+	s := NewDefaultSphere()
+	s.SetMaterial(m)
+	r := NewRayAtTime(NewPoint3(0, 0, -5), NewVec3(0, 0, 1), 0.3)
+	i := NewIntersection(4, &s)
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+	rng := newPixelRandom(0, 0, 0)
+
+	next, weight, ok := scatter(&comps, &rng)
+
+	require.True(t, ok)
+	require.True(t, next.direction.Equal(NewVec3(0, 0, -1)))
+	require.Equal(t, 0.3, next.time)
+	require.Equal(t, WHITE, weight)
+}
+
+func TestScatteringGathersTheLightOfAllTheLobes(t *testing.T) {
+	testCases := []struct {
+		diffuse, reflective float64
+	}{
+		{0, 0.25},
+		{0.5, 0.25},
+		// Phong materials may reflect more than they receive, it's up to the scene
+		{0.6, 0.6},
+	}
+
+	for _, tc := range testCases {
+		m := NewDefaultMaterial()
+		m.diffuse, m.specular, m.reflective = tc.diffuse, 0, tc.reflective
+		s := NewDefaultSphere()
+		s.SetMaterial(m)
+		r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+		i := NewIntersection(4, &s)
+		comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+		rng := newPixelRandom(0, 0, 0)
+
+		// white lobes differ only in the direction, so every path carries all their light
+		for n := 0; n < 100; n++ {
+			_, weight, ok := scatter(&comps, &rng)
+
+			require.True(t, ok)
+			require.True(t, weight.Equal(WHITE.MultScalar(tc.diffuse+tc.reflective)), "weight %v", weight)
+		}
+	}
+}
+
+// Light in the center of a closed white sphere: every point of it is lit directly with
+// the same diffuse term d, and reflects the part a of the light coming from the others.
+// So the radiance is the same everywhere, L = d + a*L, L = d / (1 - a)
+func TestPathTracingOfFurnaceConvergesToKnownRadiance(t *testing.T) {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(0, 0, 0), WHITE))
+	m := NewDefaultMaterial()
+	m.diffuse, m.specular, m.reflective = 0.5, 0, 0.25
+	furnace := NewSphere("furnace", m)
+	furnace.SetTransform(NewScalingMatrix(3, 3, 3))
+	w.Add("furnace", &furnace)
+	rng := newPixelRandom(2, 0, 0)
+
+	sum := BLACK
+	const paths = 2000
+	for i := 0; i < paths; i++ {
+		r := NewRay(NewPoint3(0, 0, 0), cosineHemisphereSample(NewVec3(0, 0, 1), rng.Float64(), rng.Float64()))
+		sum = sum.Add(w.pathTrace(r, 100, &rng))
+	}
+	average := sum.MultScalar(1. / paths)
+
+	expected := m.diffuse / (1 - m.diffuse - m.reflective)
+	require.InDelta(t, expected, average.r, 0.05)
+	require.InDelta(t, average.r, average.g, EPSILON)
+	require.InDelta(t, average.r, average.b, EPSILON)
+}
+
+// Camera in the middle of a closed cube with the light inside, so no path ever escapes.
+// The walls reflect less light than they receive
+func createClosedRoomWorld() *World {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(1, 3, -1), WHITE))
+	m := NewDefaultMaterial()
+	m.diffuse, m.specular = 0.6, 0.3
+	room := NewCube("room", m)
+	room.SetTransform(NewScalingMatrix(5, 5, 5))
+	w.Add("room", &room)
+	return w
+}
+
+func TestPathTracingOfClosedRoomIsBounded(t *testing.T) {
+	w := createClosedRoomWorld()
+	c := NewCamera(4, 4, math.Pi/2)
+	c.SetIntegrator(INTEGRATOR_PATH_TRACING)
+	c.SetSamplesPerPixel(64)
+
+	brightness := func(depth int) float64 {
+		c.SetReflectionDepth(depth)
+		image := c.Render(w)
+		sum := 0.
+		for y := 0; y < image.height; y++ {
+			for x := 0; x < image.width; x++ {
+				sum += maxComponent(image.PixelAt(x, y))
+			}
+		}
+		return sum / float64(image.width*image.height)
+	}
+
+	direct := brightness(0)
+	depth5, depth10, depth20 := brightness(5), brightness(10), brightness(20)
+
+	// every bounce loses some light, so the sum of the bounces converges
+	for _, b := range []float64{depth5, depth10, depth20} {
+		require.Greater(t, b, direct)
+		require.Less(t, b, 10*direct)
+	}
+	require.Less(t, depth20-depth10, depth10-depth5)
+}
+
+func TestOrthonormalBasis(t *testing.T) {
+	for _, n := range []Vec3{NewVec3(0, 0, 1), NewVec3(0, 0, -1), NewVec3(1, 2, 3).Normalize(), NewVec3(-1, 0.1, -0.2).Normalize()} {
+		tangent, bitangent := orthonormalBasis(n)
+
+		require.InDelta(t, 1, tangent.Magnitude(), EPSILON)
+		require.InDelta(t, 1, bitangent.Magnitude(), EPSILON)
+		require.InDelta(t, 0, tangent.Dot(bitangent), EPSILON)
+		require.InDelta(t, 0, tangent.Dot(n), EPSILON)
+		require.InDelta(t, 0, bitangent.Dot(n), EPSILON)
+	}
+}
+
+func TestDirectionSamplesAreAroundTheAxis(t *testing.T) {
+	axis := NewVec3(1, -1, 2).Normalize()
+	rng := newPixelRandom(3, 0, 0)
+
+	for i := 0; i < 100; i++ {
+		u, v := rng.Float64(), rng.Float64()
+
+		d := cosineHemisphereSample(axis, u, v)
+		require.InDelta(t, 1, d.Magnitude(), EPSILON)
+		require.GreaterOrEqual(t, d.Dot(axis), -EPSILON)
+
+		// shiny lobe is narrow
+		d = phongLobeSample(axis, 10000, u, v)
+		require.InDelta(t, 1, d.Magnitude(), EPSILON)
+		require.Greater(t, d.Dot(axis), 0.99)
+	}
+}
@@ -0,0 +1,193 @@
+package ray_tracer
+
+import "math"
+
+// Pattern paints a material with colors depending on the point of the surface.
+// Like shapes, concrete patterns work in their own pattern space, which is the
+// object space of the shape transformed with the pattern's own transform
+type Pattern interface {
+	Transform() Matrix
+	SetTransform(m *Matrix)
+	inverseTransform() *Mat4
+
+	// point is already in the pattern space
+	localColorAt(point Point3) Color
+}
+
+// Common state of all the patterns. Is supposed to be embedded into concrete patterns
+type pattern struct {
+	transform Matrix
+	inverse   Mat4
+}
+
+func newPattern() pattern {
+	return pattern{transform: *NewIdentityMatrix(4), inverse: NewIdentityMat4()}
+}
+
+// Returns a copy, so changing it doesn't affect the cached inverse
+func (p *pattern) Transform() Matrix {
+	return *p.transform.Copy()
+}
+
+// The matrix is copied, so changing it afterwards doesn't affect the pattern
+func (p *pattern) SetTransform(m *Matrix) {
+	p.transform = *m.Copy()
+	transform := m.ToMat4()
+	p.inverse = transform.Inverse()
+}
+
+func (p *pattern) inverseTransform() *Mat4 {
+	return &p.inverse
+}
+
+// Color of the pattern at the point given in the object space
+func PatternAt(p Pattern, objectPoint Point3) Color {
+	return p.localColorAt(p.inverseTransform().MulPoint(objectPoint))
+}
+
+// Color of the pattern applied to the shape at the point given in the world space.
+// If the shape is nil, the point is considered to be in the object space already
+func PatternAtShape(p Pattern, s Shape, worldPoint Point3) Color {
+	return patternAtShapeAtTime(p, s, worldPoint, 0)
+}
+
+// Same as PatternAtShape, but moving shapes are taken at the moment of time,
+// so the pattern moves along with them
+func patternAtShapeAtTime(p Pattern, s Shape, worldPoint Point3, time float64) Color {
+	objectPoint := worldPoint
+	if s != nil {
+		objectPoint = worldToObject(s, worldPoint, time)
+	}
+	return PatternAt(p, objectPoint)
+}
+
+func isEven(n float64) bool {
+	return math.Mod(math.Floor(n), 2) == 0
+}
+
+// Pattern of a single color. Used as a leaf, when patterns are nested
+type SolidPattern struct {
+	pattern
+	color Color
+}
+
+func NewSolidPattern(color Color) *SolidPattern {
+	return &SolidPattern{pattern: newPattern(), color: color}
+}
+
+func (p *SolidPattern) localColorAt(point Point3) Color {
+	return p.color
+}
+
+// Two patterns a pattern is made of. Each of them may be a nested pattern with
+// its own transform, which is applied on top of the outer pattern's one
+type patternPair struct {
+	a Pattern
+	b Pattern
+}
+
+func (p *patternPair) pick(first bool, point Point3) Color {
+	if first {
+		return PatternAt(p.a, point)
+	}
+	return PatternAt(p.b, point)
+}
+
+// Linear interpolation from a (fraction == 0) to b (fraction == 1)
+func (p *patternPair) mix(fraction float64, point Point3) Color {
+	a, b := PatternAt(p.a, point), PatternAt(p.b, point)
+	return a.Add(b.Sub(a).MultScalar(fraction))
+}
+
+// Alternates two colors every unit along X axis
+type StripePattern struct {
+	pattern
+	patternPair
+}
+
+func NewStripePattern(a, b Color) *StripePattern {
+	return NewNestedStripePattern(NewSolidPattern(a), NewSolidPattern(b))
+}
+
+func NewNestedStripePattern(a, b Pattern) *StripePattern {
+	return &StripePattern{pattern: newPattern(), patternPair: patternPair{a, b}}
+}
+
+func (p *StripePattern) localColorAt(point Point3) Color {
+	return p.pick(isEven(point.x), point)
+}
+
+// Linearly blends two colors from x == 0 to x == 1 and repeats every unit
+type GradientPattern struct {
+	pattern
+	patternPair
+}
+
+func NewGradientPattern(a, b Color) *GradientPattern {
+	return NewNestedGradientPattern(NewSolidPattern(a), NewSolidPattern(b))
+}
+
+func NewNestedGradientPattern(a, b Pattern) *GradientPattern {
+	return &GradientPattern{pattern: newPattern(), patternPair: patternPair{a, b}}
+}
+
+func (p *GradientPattern) localColorAt(point Point3) Color {
+	return p.mix(point.x-math.Floor(point.x), point)
+}
+
+// Concentric rings of unit width around Y axis
+type RingPattern struct {
+	pattern
+	patternPair
+}
+
+func NewRingPattern(a, b Color) *RingPattern {
+	return NewNestedRingPattern(NewSolidPattern(a), NewSolidPattern(b))
+}
+
+func NewNestedRingPattern(a, b Pattern) *RingPattern {
+	return &RingPattern{pattern: newPattern(), patternPair: patternPair{a, b}}
+}
+
+func (p *RingPattern) localColorAt(point Point3) Color {
+	return p.pick(isEven(math.Sqrt(point.x*point.x+point.z*point.z)), point)
+}
+
+// 3D checkerboard of unit cubes, so it works on any surface, not only planes
+type CheckerPattern struct {
+	pattern
+	patternPair
+}
+
+func NewCheckerPattern(a, b Color) *CheckerPattern {
+	return NewNestedCheckerPattern(NewSolidPattern(a), NewSolidPattern(b))
+}
+
+func NewNestedCheckerPattern(a, b Pattern) *CheckerPattern {
+	return &CheckerPattern{pattern: newPattern(), patternPair: patternPair{a, b}}
+}
+
+func (p *CheckerPattern) localColorAt(point Point3) Color {
+	return p.pick(isEven(math.Floor(point.x)+math.Floor(point.y)+math.Floor(point.z)), point)
+}
+
+// Average of all the patterns
+type BlendedPattern struct {
+	pattern
+	patterns []Pattern
+}
+
+func NewBlendedPattern(patterns ...Pattern) *BlendedPattern {
+	if len(patterns) == 0 {
+		panic("Nothing to blend!")
+	}
+	return &BlendedPattern{pattern: newPattern(), patterns: patterns}
+}
+
+func (p *BlendedPattern) localColorAt(point Point3) Color {
+	sum := BLACK
+	for _, nested := range p.patterns {
+		sum = sum.Add(PatternAt(nested, point))
+	}
+	return sum.MultScalar(1 / float64(len(p.patterns)))
+}
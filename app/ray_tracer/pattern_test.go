@@ -0,0 +1,173 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Pattern which returns the point in the pattern space as a color
+type testPattern struct {
+	pattern
+}
+
+func newTestPattern() *testPattern {
+	return &testPattern{pattern: newPattern()}
+}
+
+func (p *testPattern) localColorAt(point Point3) Color {
+	return NewColor(point.x, point.y, point.z)
+}
+
+func TestPatternsDefaultTransformationIsIdentity(t *testing.T) {
+	p := newTestPattern()
+
+	transform := p.Transform()
+	require.True(t, transform.Equal(NewIdentityMatrix(4)))
+}
+
+func TestAssigningTransformationToPattern(t *testing.T) {
+	p := newTestPattern()
+
+	p.SetTransform(NewTranslationMatrix(1, 2, 3))
+
+	transform := p.Transform()
+	require.True(t, transform.Equal(NewTranslationMatrix(1, 2, 3)))
+}
+
+func TestPatternWithObjectTransformation(t *testing.T) {
+	s := NewDefaultSphere()
+	s.SetTransform(NewScalingMatrix(2, 2, 2))
+	p := newTestPattern()
+
+	c := PatternAtShape(p, &s, NewPoint3(2, 3, 4))
+
+	require.True(t, c.Equal(NewColor(1, 1.5, 2)))
+}
+
+func TestPatternWithPatternTransformation(t *testing.T) {
+	s := NewDefaultSphere()
+	p := newTestPattern()
+	p.SetTransform(NewScalingMatrix(2, 2, 2))
+
+	c := PatternAtShape(p, &s, NewPoint3(2, 3, 4))
+
+	require.True(t, c.Equal(NewColor(1, 1.5, 2)))
+}
+
+func TestPatternWithBothObjectAndPatternTransformation(t *testing.T) {
+	s := NewDefaultSphere()
+	s.SetTransform(NewScalingMatrix(2, 2, 2))
+	p := newTestPattern()
+	p.SetTransform(NewTranslationMatrix(0.5, 1, 1.5))
+
+	c := PatternAtShape(p, &s, NewPoint3(2.5, 3, 3.5))
+
+	require.True(t, c.Equal(NewColor(0.75, 0.5, 0.25)))
+}
+
+func TestPatternOnShapeInsideGroup(t *testing.T) {
+	g := NewDefaultGroup()
+	g.SetTransform(NewScalingMatrix(2, 2, 2))
+	s := NewDefaultSphere()
+	s.SetTransform(NewTranslationMatrix(1, 0, 0))
+	g.AddChild(&s)
+	p := newTestPattern()
+
+	c := PatternAtShape(p, &s, NewPoint3(4, 2, 2))
+
+	require.True(t, c.Equal(NewColor(1, 1, 1)))
+}
+
+func TestStripePatternIsConstantInYAndZ(t *testing.T) {
+	p := NewStripePattern(WHITE, BLACK)
+
+	for _, point := range []Point3{
+		NewPoint3(0, 0, 0), NewPoint3(0, 1, 0), NewPoint3(0, 2, 0),
+		NewPoint3(0, 0, 1), NewPoint3(0, 0, 2),
+	} {
+		require.Equal(t, WHITE, PatternAt(p, point), point)
+	}
+}
+
+func TestStripePatternAlternatesInX(t *testing.T) {
+	p := NewStripePattern(WHITE, BLACK)
+
+	require.Equal(t, WHITE, PatternAt(p, NewPoint3(0, 0, 0)))
+	require.Equal(t, WHITE, PatternAt(p, NewPoint3(0.9, 0, 0)))
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(1, 0, 0)))
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(-0.1, 0, 0)))
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(-1, 0, 0)))
+	require.Equal(t, WHITE, PatternAt(p, NewPoint3(-1.1, 0, 0)))
+}
+
+func TestGradientLinearlyInterpolatesBetweenColors(t *testing.T) {
+	p := NewGradientPattern(WHITE, BLACK)
+
+	require.True(t, PatternAt(p, NewPoint3(0, 0, 0)).Equal(WHITE))
+	require.True(t, PatternAt(p, NewPoint3(0.25, 0, 0)).Equal(NewColor(0.75, 0.75, 0.75)))
+	require.True(t, PatternAt(p, NewPoint3(0.5, 0, 0)).Equal(NewColor(0.5, 0.5, 0.5)))
+	require.True(t, PatternAt(p, NewPoint3(0.75, 0, 0)).Equal(NewColor(0.25, 0.25, 0.25)))
+}
+
+func TestRingPatternExtendsInBothXAndZ(t *testing.T) {
+	p := NewRingPattern(WHITE, BLACK)
+
+	require.Equal(t, WHITE, PatternAt(p, NewPoint3(0, 0, 0)))
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(1, 0, 0)))
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(0, 0, 1)))
+	// 0.708 is just slightly more than sqrt(2)/2
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(0.708, 0, 0.708)))
+}
+
+func TestCheckersRepeatInEveryDimension(t *testing.T) {
+	p := NewCheckerPattern(WHITE, BLACK)
+
+	for _, tc := range []struct {
+		point  Point3
+		expect Color
+	}{
+		{NewPoint3(0, 0, 0), WHITE},
+		{NewPoint3(0.99, 0, 0), WHITE},
+		{NewPoint3(1.01, 0, 0), BLACK},
+		{NewPoint3(0, 0.99, 0), WHITE},
+		{NewPoint3(0, 1.01, 0), BLACK},
+		{NewPoint3(0, 0, 0.99), WHITE},
+		{NewPoint3(0, 0, 1.01), BLACK},
+		{NewPoint3(-0.5, 0, -0.5), WHITE},
+		{NewPoint3(-0.5, 0, 0.5), BLACK},
+	} {
+		require.Equal(t, tc.expect, PatternAt(p, tc.point), tc.point)
+	}
+}
+
+func TestSolidPatternIsTheSameEverywhere(t *testing.T) {
+	p := NewSolidPattern(RED)
+
+	require.Equal(t, RED, PatternAt(p, NewPoint3(0, 0, 0)))
+	require.Equal(t, RED, PatternAt(p, NewPoint3(-3.5, 100, 0.1)))
+}
+
+func TestNestedPatternsUseTheirOwnTransforms(t *testing.T) {
+	narrow := NewStripePattern(RED, GREEN)
+	narrow.SetTransform(NewScalingMatrix(0.5, 1, 1))
+	p := NewNestedCheckerPattern(narrow, NewSolidPattern(BLUE))
+
+	require.Equal(t, RED, PatternAt(p, NewPoint3(0.25, 0, 0)))
+	require.Equal(t, GREEN, PatternAt(p, NewPoint3(0.75, 0, 0)))
+	require.Equal(t, BLUE, PatternAt(p, NewPoint3(1.25, 0, 0)))
+	require.Equal(t, RED, PatternAt(p, NewPoint3(2.25, 0, 0)))
+}
+
+func TestBlendedPatternAveragesPatterns(t *testing.T) {
+	horizontal := NewStripePattern(WHITE, BLACK)
+	vertical := NewStripePattern(WHITE, BLACK)
+	vertical.SetTransform(NewRotationYMatrix(math.Pi / 2))
+	p := NewBlendedPattern(horizontal, vertical)
+
+	require.True(t, PatternAt(p, NewPoint3(0.5, 0, -0.5)).Equal(WHITE))
+	require.True(t, PatternAt(p, NewPoint3(1.5, 0, -0.5)).Equal(NewColor(0.5, 0.5, 0.5)))
+	require.True(t, PatternAt(p, NewPoint3(1.5, 0, 0.5)).Equal(BLACK))
+	require.Panics(t, func() { NewBlendedPattern() })
+}
@@ -0,0 +1,43 @@
+package ray_tracer
+
+import "math"
+
+// Infinite xz plane (y == 0) with a normal pointing to the +Y
+type Plane struct {
+	shape
+}
+
+func NewPlane(id string, material Material) Plane {
+	return Plane{shape: newShape(id, material)}
+}
+
+func NewDefaultPlane() Plane {
+	return NewPlane("plane_id", NewDefaultMaterial())
+}
+
+func (p *Plane) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(p, r)
+}
+
+func (p *Plane) NormalAt(worldPoint Point3) Vec3 {
+	return NormalAt(p, worldPoint, Intersection{})
+}
+
+func (p *Plane) localIntersectWith(r *Ray) []Intersection {
+	// Ray parallel to the plane (or coplanar with it) never hits it
+	if math.Abs(r.direction.y) < EPSILON {
+		return []Intersection{}
+	}
+
+	t := -r.origin.y / r.direction.y
+	return []Intersection{NewIntersection(t, p)}
+}
+
+func (p *Plane) Bounds() BoundingBox {
+	inf := math.Inf(1)
+	return NewBoundingBox(NewPoint3(-inf, 0, -inf), NewPoint3(inf, 0, inf))
+}
+
+func (p *Plane) localNormalAt(point Point3, hit Intersection) Vec3 {
+	return NewVec3(0, 1, 0)
+}
@@ -0,0 +1,94 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalOfPlaneIsConstantEverywhere(t *testing.T) {
+	p := NewDefaultPlane()
+
+	n1 := p.localNormalAt(NewPoint3(0, 0, 0), Intersection{})
+	n2 := p.localNormalAt(NewPoint3(10, 0, -10), Intersection{})
+	n3 := p.localNormalAt(NewPoint3(-5, 0, 150), Intersection{})
+
+	expect := NewVec3(0, 1, 0)
+	require.True(t, n1.Equal(expect))
+	require.True(t, n2.Equal(expect))
+	require.True(t, n3.Equal(expect))
+}
+
+func TestIntersectWithRayParallelToPlane(t *testing.T) {
+	p := NewDefaultPlane()
+	r := NewRay(NewPoint3(0, 10, 0), NewVec3(0, 0, 1))
+
+	xs := p.localIntersectWith(&r)
+
+	require.Len(t, xs, 0)
+}
+
+func TestIntersectWithCoplanarRay(t *testing.T) {
+	p := NewDefaultPlane()
+	r := NewRay(NewPoint3(0, 0, 0), NewVec3(0, 0, 1))
+
+	xs := p.localIntersectWith(&r)
+
+	require.Len(t, xs, 0)
+}
+
+func TestRayIntersectingPlaneFromAbove(t *testing.T) {
+	p := NewDefaultPlane()
+	r := NewRay(NewPoint3(0, 1, 0), NewVec3(0, -1, 0))
+
+	xs := p.localIntersectWith(&r)
+
+	require.Len(t, xs, 1)
+	require.EqualValues(t, 1, xs[0].time)
+	require.EqualValues(t, &p, xs[0].object)
+}
+
+func TestRayIntersectingPlaneFromBelow(t *testing.T) {
+	p := NewDefaultPlane()
+	r := NewRay(NewPoint3(0, -1, 0), NewVec3(0, 1, 0))
+
+	xs := p.localIntersectWith(&r)
+
+	require.Len(t, xs, 1)
+	require.EqualValues(t, 1, xs[0].time)
+	require.EqualValues(t, &p, xs[0].object)
+}
+
+func TestIntersectingTransformedPlane(t *testing.T) {
+	p := NewDefaultPlane()
+	p.SetTransform(NewTranslationMatrix(0, -1, 0))
+	r := NewRay(NewPoint3(0, 1, 0), NewVec3(0, -1, 0))
+
+	xs := p.IntersectWith(&r)
+
+	require.Len(t, xs, 1)
+	require.EqualValues(t, 2, xs[0].time)
+}
+
+func TestNormalOfRotatedPlane(t *testing.T) {
+	p := NewDefaultPlane()
+	p.SetTransform(NewRotationXMatrix(-math.Pi / 2))
+
+	n := p.NormalAt(NewPoint3(0, 0, 0))
+
+	require.True(t, n.Equal(NewVec3(0, 0, -1)))
+}
+
+func TestShadingHitOnPlane(t *testing.T) {
+	w := NewWorld()
+	w.SetLight(NewPointLight(NewPoint3(0, 10, 0), WHITE))
+	floor := NewDefaultPlane()
+	w.Add("floor", &floor)
+	r := NewRay(NewPoint3(0, 1, 0), NewVec3(0, -1, 0))
+
+	res := w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH)
+
+	i := 0.1 + 0.9 + 0.9
+	require.True(t, res.Equal(NewColor(i, i, i)))
+}
@@ -0,0 +1,43 @@
+package ray_tracer
+
+// Point in 3D space. Unlike Tuple it can't be mixed up with a vector: only
+// meaningful operations are defined, so misuse doesn't compile
+type Point3 struct {
+	x float64
+	y float64
+	z float64
+}
+
+func NewPoint3(x, y, z float64) Point3 {
+	return Point3{x, y, z}
+}
+
+func (a Point3) Equal(b Point3) bool {
+	return equal_fp(a.x, b.x) && equal_fp(a.y, b.y) && equal_fp(a.z, b.z)
+}
+
+// point + vector = point
+func (p Point3) Add(v Vec3) Point3 {
+	return Point3{p.x + v.x, p.y + v.y, p.z + v.z}
+}
+
+// point - point = vector pointing from b to a
+func (a Point3) Sub(b Point3) Vec3 {
+	return Vec3{a.x - b.x, a.y - b.y, a.z - b.z}
+}
+
+// point - vector = point
+func (p Point3) SubVec(v Vec3) Point3 {
+	return Point3{p.x - v.x, p.y - v.y, p.z - v.z}
+}
+
+func (p Point3) ToTuple() Tuple {
+	return NewPoint(p.x, p.y, p.z)
+}
+
+func (t Tuple) ToPoint3() Point3 {
+	if !t.IsPoint() {
+		panic("Only a point can be converted to Point3!")
+	}
+	return Point3{t.x, t.y, t.z}
+}
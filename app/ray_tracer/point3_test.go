@@ -0,0 +1,50 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoint3Equality(t *testing.T) {
+	require.True(t, NewPoint3(1, 2, 3).Equal(NewPoint3(1, 2, 3)))
+	require.False(t, NewPoint3(1, 2, 3).Equal(NewPoint3(1, 2, 3.1)))
+}
+
+func TestAddingVectorToPoint3GivesPoint(t *testing.T) {
+	p := NewPoint3(3, -2, 5)
+	v := NewVec3(-2, 3, 1)
+
+	require.Equal(t, NewPoint3(1, 1, 6), p.Add(v))
+}
+
+func TestSubtractingTwoPoint3GivesVector(t *testing.T) {
+	p1 := NewPoint3(3, 2, 1)
+	p2 := NewPoint3(5, 6, 7)
+
+	require.Equal(t, NewVec3(-2, -4, -6), p1.Sub(p2))
+}
+
+func TestSubtractingVectorFromPoint3GivesPoint(t *testing.T) {
+	p := NewPoint3(3, 2, 1)
+	v := NewVec3(5, 6, 7)
+
+	require.Equal(t, NewPoint3(-2, -4, -6), p.SubVec(v))
+}
+
+func TestConvertingPoint3ToTupleAndBack(t *testing.T) {
+	p := NewPoint3(1, 2, 3)
+
+	require.True(t, p.ToTuple().Equal(NewPoint(1, 2, 3)))
+	require.Equal(t, p, NewPoint(1, 2, 3).ToPoint3())
+	require.Panics(t, func() { NewVector(1, 2, 3).ToPoint3() })
+}
+
+func TestMultiplyingPoint3ByTranslationMatrix(t *testing.T) {
+	m := NewTranslationMatrix(5, -3, 2)
+	m4 := m.ToMat4()
+	p := NewPoint3(-3, 4, 5)
+
+	require.Equal(t, NewPoint3(2, 1, 7), m.MulPoint(p))
+	require.Equal(t, NewPoint3(2, 1, 7), m4.MulPoint(p))
+}
@@ -0,0 +1,38 @@
+package ray_tracer
+
+import "math"
+
+// How the camera maps the directions of the scene onto the canvas
+type Projection int
+
+const (
+	// Pinhole (or thin lens) camera, fieldOfView is the angle across the larger side of the canvas
+	PROJECTION_PERSPECTIVE Projection = iota
+	// Parallel rays, sizes don't change with distance. The width of the view is set by
+	// Camera.SetOrthographicWidth
+	PROJECTION_ORTHOGRAPHIC
+	// Equidistant fisheye: the angle from the view direction grows linearly with the
+	// distance from the canvas center. fieldOfView is the angle across the larger side
+	// of the canvas and may be up to 2*pi. The image is a circle, the rest is black
+	PROJECTION_FISHEYE
+	// Full 360 x 180 degrees panorama: longitude goes along the width of the canvas,
+	// latitude along the height. Canvas should have 2:1 aspect ratio
+	PROJECTION_EQUIRECTANGULAR
+)
+
+// Direction of the ray through the point (x, y) of the canvas in the camera space.
+// x and y are in [-1, 1] along the larger side of the canvas, positive x is to the left
+// and positive y is up, camera looks towards -z
+func fisheyeDirection(x, y, fieldOfView float64) Vec3 {
+	theta := math.Sqrt(x*x+y*y) * fieldOfView / 2
+	phi := math.Atan2(y, x)
+	return NewVec3(math.Sin(theta)*math.Cos(phi), math.Sin(theta)*math.Sin(phi), -math.Cos(theta))
+}
+
+// u and v are in [0, 1] from the top left corner of the canvas
+func equirectangularDirection(u, v float64) Vec3 {
+	// the center of the canvas looks straight ahead
+	longitude := (0.5 - u) * 2 * math.Pi
+	latitude := (0.5 - v) * math.Pi
+	return NewVec3(math.Cos(latitude)*math.Sin(longitude), math.Sin(latitude), -math.Cos(latitude)*math.Cos(longitude))
+}
@@ -1,25 +1,33 @@
 package ray_tracer
 
-// TODO: Can I do new types Point and Vector inherited from Tuple?
 type Ray struct {
-	origin    Tuple
-	direction Tuple
+	origin    Point3
+	direction Vec3
+	// moment the ray is cast at, moving shapes are intersected in their position at this
+	// time. Not to be confused with the time of the intersections, which is the distance along the ray
+	time float64
 }
 
-func NewRay(origin, direction Tuple) Ray {
-	if !origin.IsPoint() {
-		panic("Origin must be a point!")
-	}
-	if !direction.IsVector() {
-		panic("Direction must be a vector!")
-	}
-	return Ray{origin, direction}
+func NewRay(origin Point3, direction Vec3) Ray {
+	return Ray{origin: origin, direction: direction}
 }
 
-func (r *Ray) CalcPosition(time float64) Tuple {
+func NewRayAtTime(origin Point3, direction Vec3, time float64) Ray {
+	return Ray{origin: origin, direction: direction, time: time}
+}
+
+func (r *Ray) Time() float64 {
+	return r.time
+}
+
+func (r *Ray) CalcPosition(time float64) Point3 {
 	return r.origin.Add(r.direction.Mul(time))
 }
 
 func (r *Ray) ApplyTransform(m *Matrix) Ray {
-	return NewRay(m.MulTuple(r.origin), m.MulTuple(r.direction))
+	return NewRayAtTime(m.MulPoint(r.origin), m.MulVec(r.direction), r.time)
+}
+
+func (r *Ray) ApplyMat4(m *Mat4) Ray {
+	return NewRayAtTime(m.MulPoint(r.origin), m.MulVec(r.direction), r.time)
 }
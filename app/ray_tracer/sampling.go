@@ -0,0 +1,210 @@
+package ray_tracer
+
+import (
+	"math"
+	"math/bits"
+)
+
+// How the sample points are placed inside of a pixel
+type PixelSampling int
+
+const (
+	// Centers of the cells of an evenly divided pixel
+	SAMPLING_REGULAR PixelSampling = iota
+	// Random point in every cell of an evenly divided pixel
+	SAMPLING_JITTERED
+	// Low discrepancy sequences: points are spread evenly, but without regular
+	// patterns, for any number of samples
+	SAMPLING_HALTON
+	SAMPLING_SOBOL
+)
+
+// Reconstruction filter, which weights the samples depending on their distance
+// from the pixel center. Filters wider than a pixel take samples from the
+// neighbouring pixels too, which makes the image smoother
+type PixelFilter int
+
+const (
+	FILTER_BOX PixelFilter = iota
+	FILTER_TENT
+	FILTER_GAUSSIAN
+	FILTER_MITCHELL
+)
+
+// Half of the width of the filter, in pixels
+func (f PixelFilter) radius() float64 {
+	switch f {
+	case FILTER_TENT:
+		return 1
+	case FILTER_GAUSSIAN:
+		return 1.5
+	case FILTER_MITCHELL:
+		return 2
+	default:
+		return 0.5
+	}
+}
+
+// Weight of the sample at (x, y) from the pixel center. Filters are separable,
+// so the weight is a product of the 1D weights along both axes
+func (f PixelFilter) weight(x, y float64) float64 {
+	return f.weight1D(math.Abs(x)) * f.weight1D(math.Abs(y))
+}
+
+func (f PixelFilter) weight1D(x float64) float64 {
+	r := f.radius()
+	if x > r {
+		return 0
+	}
+
+	switch f {
+	case FILTER_TENT:
+		return 1 - x/r
+	case FILTER_GAUSSIAN:
+		const alpha = 2
+		// shifted down, so it smoothly goes to 0 at the radius
+		return math.Exp(-alpha*x*x) - math.Exp(-alpha*r*r)
+	case FILTER_MITCHELL:
+		// B = C = 1/3 is the balance between blurring and ringing recommended by Mitchell and Netravali
+		const b, c = 1. / 3, 1. / 3
+		if x < 1 {
+			return ((12-9*b-6*c)*x*x*x + (-18+12*b+6*c)*x*x + (6 - 2*b)) / 6
+		}
+		return ((-b-6*c)*x*x*x + (6*b+30*c)*x*x + (-12*b-48*c)*x + (8*b + 24*c)) / 6
+	default:
+		return 1
+	}
+}
+
+// Small and fast generator of pseudo random numbers (SplitMix64). Every pixel
+// gets its own one, so the images are reproducible even when rendered in parallel
+type pixelRandom struct {
+	state uint64
+}
+
+func newPixelRandom(seed int64, x, y int) pixelRandom {
+	return pixelRandom{state: splitMix64(uint64(seed) ^ splitMix64(uint64(x)<<32|uint64(uint32(y))))}
+}
+
+// Returns a number in [0, 1)
+func (r *pixelRandom) Float64() float64 {
+	r.state += 0x9e3779b97f4a7c15
+	// 53 bits is the precision of float64
+	return float64(splitMix64(r.state)>>11) / (1 << 53)
+}
+
+// Finalizer of the SplitMix64 generator, it scatters close inputs far apart
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// Splits n samples into the grid as close to a square as possible
+func sampleGrid(n int) (columns, rows int) {
+	rows = int(math.Sqrt(float64(n)))
+	for n%rows != 0 {
+		rows--
+	}
+	return n / rows, rows
+}
+
+// Radical inverse of i in base 2 (van der Corput sequence) is the first dimension of
+// both Halton and Sobol sequences
+func radicalInverseBase2(i uint32) float64 {
+	return float64(bits.Reverse32(i)) / (1 << 32)
+}
+
+func radicalInverse(i uint32, base uint32) float64 {
+	result, fraction := 0., 1/float64(base)
+	for ; i > 0; i /= base {
+		result += float64(i%base) * fraction
+		fraction /= float64(base)
+	}
+	return result
+}
+
+// The second dimension of Sobol sequence. Its direction numbers are v(k) = v(k-1) ^ (v(k-1) >> 1)
+func sobolSecondDimension(i uint32) float64 {
+	result := uint32(0)
+	for v := uint32(1 << 31); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			result ^= v
+		}
+		v ^= v >> 1
+	}
+	return float64(result) / (1 << 32)
+}
+
+// n sample points inside of a unit square
+func pixelSamples(sampling PixelSampling, n int, rng *pixelRandom) [][2]float64 {
+	samples := make([][2]float64, 0, n)
+
+	switch sampling {
+	case SAMPLING_REGULAR, SAMPLING_JITTERED:
+		columns, rows := sampleGrid(n)
+		for row := 0; row < rows; row++ {
+			for column := 0; column < columns; column++ {
+				offsetX, offsetY := 0.5, 0.5
+				if sampling == SAMPLING_JITTERED {
+					offsetX, offsetY = rng.Float64(), rng.Float64()
+				}
+				samples = append(samples, [2]float64{
+					(float64(column) + offsetX) / float64(columns),
+					(float64(row) + offsetY) / float64(rows),
+				})
+			}
+		}
+
+	case SAMPLING_HALTON, SAMPLING_SOBOL:
+		// the sequences are the same for every pixel, random shift (Cranley-Patterson
+		// rotation) hides the repetition
+		shiftX, shiftY := rng.Float64(), rng.Float64()
+		for i := 0; i < n; i++ {
+			x := radicalInverseBase2(uint32(i))
+			y := sobolSecondDimension(uint32(i))
+			if sampling == SAMPLING_HALTON {
+				y = radicalInverse(uint32(i), 3)
+			}
+			samples = append(samples, [2]float64{fraction(x + shiftX), fraction(y + shiftY)})
+		}
+	}
+	return samples
+}
+
+// Maps a point of the unit square onto the unit disk, keeping the relative distances
+// between the points (Shirley-Chiu concentric mapping), so stratified samples stay stratified
+func concentricDiskSample(u, v float64) (x, y float64) {
+	sx, sy := 2*u-1, 2*v-1
+	if sx == 0 && sy == 0 {
+		return 0, 0
+	}
+
+	var radius, theta float64
+	if math.Abs(sx) > math.Abs(sy) {
+		radius, theta = sx, math.Pi/4*(sy/sx)
+	} else {
+		radius, theta = sy, math.Pi/2-math.Pi/4*(sx/sy)
+	}
+	return radius * math.Cos(theta), radius * math.Sin(theta)
+}
+
+// Shuffles the samples in place (Fisher-Yates), so that two sets of samples may be
+// paired without correlation between them
+func shuffle[T any](samples []T, rng *pixelRandom) {
+	for i := len(samples) - 1; i > 0; i-- {
+		j := int(rng.Float64() * float64(i+1))
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+}
+
+// n jittered numbers in [0, 1), one per every 1/n interval, in random order
+func stratifiedSamples1D(n int, rng *pixelRandom) []float64 {
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = (float64(i) + rng.Float64()) / float64(n)
+	}
+	shuffle(samples, rng)
+	return samples
+}
@@ -0,0 +1,150 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplesAreSplitIntoTheGridCloseToASquare(t *testing.T) {
+	tests := []struct {
+		n, columns, rows int
+	}{
+		{1, 1, 1},
+		{4, 2, 2},
+		{6, 3, 2},
+		{7, 7, 1},
+		{16, 4, 4},
+	}
+
+	for _, test := range tests {
+		columns, rows := sampleGrid(test.n)
+
+		require.Equal(t, test.columns, columns, "%d samples", test.n)
+		require.Equal(t, test.rows, rows, "%d samples", test.n)
+	}
+}
+
+func TestRegularSamplesAreInTheCentersOfTheCells(t *testing.T) {
+	rng := newPixelRandom(0, 0, 0)
+	samples := pixelSamples(SAMPLING_REGULAR, 4, &rng)
+
+	require.Equal(t, [][2]float64{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}}, samples)
+}
+
+func TestJitteredSamplesStayInTheirCells(t *testing.T) {
+	rng := newPixelRandom(0, 3, 4)
+	samples := pixelSamples(SAMPLING_JITTERED, 9, &rng)
+
+	require.Len(t, samples, 9)
+	for i, s := range samples {
+		column, row := float64(i%3), float64(i/3)
+		require.True(t, s[0] >= column/3 && s[0] < (column+1)/3, "sample %d: %v", i, s)
+		require.True(t, s[1] >= row/3 && s[1] < (row+1)/3, "sample %d: %v", i, s)
+	}
+}
+
+func TestLowDiscrepancySequences(t *testing.T) {
+	require.Equal(t, []float64{0, 0.5, 0.25, 0.75, 0.125}, []float64{
+		radicalInverseBase2(0), radicalInverseBase2(1), radicalInverseBase2(2), radicalInverseBase2(3), radicalInverseBase2(4),
+	})
+	require.InDeltaSlice(t, []float64{0, 1. / 3, 2. / 3, 1. / 9, 4. / 9}, []float64{
+		radicalInverse(0, 3), radicalInverse(1, 3), radicalInverse(2, 3), radicalInverse(3, 3), radicalInverse(4, 3),
+	}, EPSILON)
+	require.Equal(t, []float64{0, 0.5, 0.75, 0.25, 0.625}, []float64{
+		sobolSecondDimension(0), sobolSecondDimension(1), sobolSecondDimension(2), sobolSecondDimension(3), sobolSecondDimension(4),
+	})
+}
+
+func TestLowDiscrepancySamplesFillEveryStratum(t *testing.T) {
+	for _, sampling := range []PixelSampling{SAMPLING_HALTON, SAMPLING_SOBOL} {
+		rng := newPixelRandom(7, 1, 2)
+		samples := pixelSamples(sampling, 16, &rng)
+
+		// shifted sequence still has exactly one point in every quarter along X
+		quarters := [4]int{}
+		for _, s := range samples {
+			require.True(t, s[0] >= 0 && s[0] < 1 && s[1] >= 0 && s[1] < 1)
+			quarters[int(s[0]*4)]++
+		}
+		require.Equal(t, [4]int{4, 4, 4, 4}, quarters, "sampling %d", sampling)
+	}
+}
+
+func TestPixelRandomIsReproducible(t *testing.T) {
+	a, b, other := newPixelRandom(42, 5, 6), newPixelRandom(42, 5, 6), newPixelRandom(42, 6, 5)
+
+	for i := 0; i < 10; i++ {
+		x := a.Float64()
+		require.True(t, x >= 0 && x < 1)
+		require.Equal(t, x, b.Float64())
+		require.NotEqual(t, x, other.Float64())
+	}
+}
+
+func TestReconstructionFilters(t *testing.T) {
+	tests := []struct {
+		filter PixelFilter
+		x      float64
+		expect float64
+	}{
+		{FILTER_BOX, 0, 1},
+		{FILTER_BOX, 0.4, 1},
+		{FILTER_BOX, 0.6, 0},
+		{FILTER_TENT, 0, 1},
+		{FILTER_TENT, -0.25, 0.75},
+		{FILTER_TENT, 1.5, 0},
+		{FILTER_GAUSSIAN, 0, 0.98889},
+		{FILTER_GAUSSIAN, 1.5, 0},
+		{FILTER_MITCHELL, 0, 8. / 9},
+		{FILTER_MITCHELL, 1, 1. / 18},
+		{FILTER_MITCHELL, 1.5, -0.03472},
+		{FILTER_MITCHELL, 2, 0},
+	}
+
+	for _, test := range tests {
+		require.InDelta(t, test.expect, test.filter.weight1D(math.Abs(test.x)), EPSILON, "filter %d at %v", test.filter, test.x)
+	}
+}
+
+func TestConcentricDiskSampleMapsTheSquareOntoTheUnitDisk(t *testing.T) {
+	tests := []struct {
+		u, v float64
+		x, y float64
+	}{
+		{0.5, 0.5, 0, 0},
+		{1, 0.5, 1, 0},
+		{0.5, 0, 0, -1},
+		{1, 1, COS45, COS45},
+		{0, 0, -COS45, -COS45},
+	}
+
+	for _, test := range tests {
+		x, y := concentricDiskSample(test.u, test.v)
+
+		require.InDelta(t, test.x, x, EPSILON, "(%v, %v)", test.u, test.v)
+		require.InDelta(t, test.y, y, EPSILON, "(%v, %v)", test.u, test.v)
+	}
+}
+
+func TestShuffledSamplesArePermuted(t *testing.T) {
+	rng := newPixelRandom(1, 2, 3)
+	samples := pixelSamples(SAMPLING_REGULAR, 16, &rng)
+	shuffled := pixelSamples(SAMPLING_REGULAR, 16, &rng)
+	shuffle(shuffled, &rng)
+
+	require.NotEqual(t, samples, shuffled)
+	require.ElementsMatch(t, samples, shuffled)
+}
+
+func TestStratifiedSamples1DHaveOneSampleInEveryInterval(t *testing.T) {
+	rng := newPixelRandom(1, 2, 3)
+	samples := stratifiedSamples1D(8, &rng)
+
+	intervals := [8]int{}
+	for _, s := range samples {
+		intervals[int(s*8)]++
+	}
+	require.Equal(t, [8]int{1, 1, 1, 1, 1, 1, 1, 1}, intervals)
+}
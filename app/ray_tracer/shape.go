@@ -0,0 +1,192 @@
+package ray_tracer
+
+// Shape is anything that can be placed into the World and hit by a ray.
+// Concrete shapes only know how to intersect and compute normals in their own
+// object space, transformations from/to the world space are handled here.
+type Shape interface {
+	Id() string
+	Transform() Matrix
+	SetTransform(m *Matrix)
+	Material() Material
+	SetMaterial(m Material)
+	// Group (or CSG) the shape belongs to or nil, if it's a top level shape
+	Parent() Shape
+	setParent(p Shape)
+	// World the top level shape was added to, see World.Add
+	setWorld(w *World)
+	// Drops the bounding volume hierarchies the shape is part of, because its bounds changed
+	dropBVHs()
+	// Box containing the whole shape in its object space
+	Bounds() BoundingBox
+	// Keyframes of the transform, if the shape moves. See SetMotion
+	Motion() []Keyframe
+	SetMotion(keyframes ...Keyframe)
+	// Inverse of the transform and its transpose at the moment of time. They
+	// are cached, unless the shape moves
+	inverseTransform(time float64) Mat4
+	normalTransform(time float64) Mat4
+
+	// ray is already in the object space
+	localIntersectWith(r *Ray) []Intersection
+	// point is already in the object space, returned normal is in the object space too.
+	// hit is the intersection which produced the point (e.g. to interpolate normals using u/v)
+	localNormalAt(point Point3, hit Intersection) Vec3
+}
+
+// Common state of all the shapes. Is supposed to be embedded into concrete shapes
+type shape struct {
+	id        string
+	transform Matrix
+	// Inverse is needed for every ray, so it's calculated only once in SetTransform.
+	// Inverse transposed is used to transform normals to the world space
+	inverse          Mat4
+	inverseTranspose Mat4
+	// nil if the shape doesn't move
+	motion    *motion
+	keyframes []Keyframe
+	material  Material
+	parent    Shape
+	world     *World
+}
+
+func newShape(id string, material Material) shape {
+	return shape{
+		id:               id,
+		transform:        *NewIdentityMatrix(4),
+		inverse:          NewIdentityMat4(),
+		inverseTranspose: NewIdentityMat4(),
+		material:         material,
+	}
+}
+
+func (s *shape) Id() string {
+	return s.id
+}
+
+// Returns a copy, so changing it doesn't affect the cached inverse
+func (s *shape) Transform() Matrix {
+	return *s.transform.Copy()
+}
+
+// The matrix is copied, so changing it afterwards doesn't affect the shape.
+// Stops the motion, if there was any
+func (s *shape) SetTransform(m *Matrix) {
+	s.transform = *m.Copy()
+	transform := m.ToMat4()
+	s.inverse = transform.Inverse()
+	s.inverseTranspose = s.inverse.Transpose()
+	s.motion, s.keyframes = nil, nil
+	s.dropBVHs()
+}
+
+func (s *shape) Motion() []Keyframe {
+	return s.keyframes
+}
+
+// Makes the shape move: its transform at the time of the ray is interpolated between
+// the keyframes (translation and scaling linearly, rotation along the shortest arc, so
+// keyframes should be less than half a turn apart). Transform is the one of the first keyframe.
+// Calling it without keyframes stops the motion
+func (s *shape) SetMotion(keyframes ...Keyframe) {
+	if len(keyframes) == 0 {
+		s.SetTransform(&s.transform)
+		return
+	}
+
+	m := newMotion(keyframes)
+	s.SetTransform(m.keyframes[0].transform.ToMatrix())
+	s.motion = m
+	s.keyframes = append([]Keyframe{}, keyframes...)
+}
+
+func (s *shape) inverseTransform(time float64) Mat4 {
+	if s.motion == nil {
+		return s.inverse
+	}
+	return s.motion.inverseAt(time)
+}
+
+func (s *shape) normalTransform(time float64) Mat4 {
+	if s.motion == nil {
+		return s.inverseTranspose
+	}
+	inverse := s.motion.inverseAt(time)
+	return inverse.Transpose()
+}
+
+func (s *shape) Material() Material {
+	return s.material
+}
+
+func (s *shape) SetMaterial(m Material) {
+	s.material = m
+}
+
+func (s *shape) Parent() Shape {
+	return s.parent
+}
+
+func (s *shape) setParent(p Shape) {
+	s.parent = p
+}
+
+func (s *shape) setWorld(w *World) {
+	s.world = w
+}
+
+// Hierarchies of all the groups the shape is nested in and of the world are
+// built over its bounds, so they are outdated when it moves
+func (s *shape) dropBVHs() {
+	if s.parent != nil {
+		s.parent.dropBVHs()
+	} else if s.world != nil {
+		s.world.bvh = nil
+	}
+}
+
+func IntersectWith(s Shape, r *Ray) []Intersection {
+	// Inverse-transform the ray instead of transforming the shape.
+	// It makes the math easier.
+	inverse := s.inverseTransform(r.time)
+	localRay := r.ApplyMat4(&inverse)
+	return s.localIntersectWith(&localRay)
+}
+
+// Converts a point from the world space to the object space of the shape,
+// going through all the groups the shape is nested in. Moving shapes are taken
+// at the moment of time
+func worldToObject(s Shape, worldPoint Point3, time float64) Point3 {
+	if s.Parent() != nil {
+		worldPoint = worldToObject(s.Parent(), worldPoint, time)
+	}
+
+	inverse := s.inverseTransform(time)
+	return inverse.MulPoint(worldPoint)
+}
+
+// Converts a normal from the object space of the shape to the world space,
+// going through all the groups the shape is nested in
+func normalToWorld(s Shape, normal Vec3, time float64) Vec3 {
+	// For usual point we could just multiply by a shape's transformation matrix to
+	// transform vector from Object space to World space. But for normals it doesn't work,
+	// because it transforms them in undesired way (e.g. squishing normals along with squishing
+	// the object)
+	normalTransform := s.normalTransform(time)
+	normal = normalTransform.MulVec(normal).Normalize()
+
+	if s.Parent() != nil {
+		normal = normalToWorld(s.Parent(), normal, time)
+	}
+	return normal
+}
+
+func NormalAt(s Shape, worldPoint Point3, hit Intersection) Vec3 {
+	return normalAtTime(s, worldPoint, hit, 0)
+}
+
+// Same as NormalAt, but moving shapes are taken at the moment of time
+func normalAtTime(s Shape, worldPoint Point3, hit Intersection, time float64) Vec3 {
+	localPoint := worldToObject(s, worldPoint, time)
+	localNormal := s.localNormalAt(localPoint, hit)
+	return normalToWorld(s, localNormal, time)
+}
@@ -0,0 +1,170 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Shape which remembers the ray it was intersected with in the object space
+type testShape struct {
+	shape
+	savedRay Ray
+}
+
+func newTestShape() *testShape {
+	return &testShape{shape: newShape("test_shape", NewDefaultMaterial())}
+}
+
+func (s *testShape) localIntersectWith(r *Ray) []Intersection {
+	s.savedRay = *r
+	return []Intersection{}
+}
+
+func (s *testShape) Bounds() BoundingBox {
+	return NewBoundingBox(NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1))
+}
+
+func (s *testShape) localNormalAt(point Point3, hit Intersection) Vec3 {
+	return NewVec3(point.x, point.y, point.z)
+}
+
+func TestShapesDefaultTransformationIsIdentity(t *testing.T) {
+	s := newTestShape()
+
+	transform := s.Transform()
+	require.True(t, transform.Equal(NewIdentityMatrix(4)))
+}
+
+func TestAssigningTransformationToShape(t *testing.T) {
+	s := newTestShape()
+
+	s.SetTransform(NewTranslationMatrix(2, 3, 4))
+
+	transform := s.Transform()
+	require.True(t, transform.Equal(NewTranslationMatrix(2, 3, 4)))
+}
+
+func TestChangingReturnedTransformDoesNotAffectShape(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewTranslationMatrix(2, 3, 4))
+
+	transform := s.Transform()
+	transform.data[0][3] = 10
+
+	original := s.Transform()
+	require.True(t, original.Equal(NewTranslationMatrix(2, 3, 4)))
+}
+
+func TestShapesDefaultMaterial(t *testing.T) {
+	s := newTestShape()
+
+	require.Equal(t, NewDefaultMaterial(), s.Material())
+}
+
+func TestAssigningMaterialToShape(t *testing.T) {
+	s := newTestShape()
+	m := NewDefaultMaterial()
+	m.ambient = 1
+
+	s.SetMaterial(m)
+
+	require.Equal(t, m, s.Material())
+}
+
+func TestIntersectingScaledShapeWithRay(t *testing.T) {
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	s := newTestShape()
+	s.SetTransform(NewScalingMatrix(2, 2, 2))
+
+	IntersectWith(s, &r)
+
+	require.True(t, s.savedRay.origin.Equal(NewPoint3(0, 0, -2.5)))
+	require.True(t, s.savedRay.direction.Equal(NewVec3(0, 0, 0.5)))
+}
+
+func TestIntersectingTranslatedShapeWithRay(t *testing.T) {
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+	s := newTestShape()
+	s.SetTransform(NewTranslationMatrix(5, 0, 0))
+
+	IntersectWith(s, &r)
+
+	require.True(t, s.savedRay.origin.Equal(NewPoint3(-5, 0, -5)))
+	require.True(t, s.savedRay.direction.Equal(NewVec3(0, 0, 1)))
+}
+
+func TestComputingNormalOnTranslatedShape(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewTranslationMatrix(0, 1, 0))
+
+	n := NormalAt(s, NewPoint3(0, 1.70711, -0.70711), Intersection{})
+
+	require.True(t, n.Equal(NewVec3(0, 0.70711, -0.70711)))
+}
+
+func TestComputingNormalOnTransformedShape(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewIdentityMatrix(4).RotateZ(math.Pi/5).Scale(1, 0.5, 1))
+
+	n := NormalAt(s, NewPoint3(0, COS45, -COS45), Intersection{})
+
+	require.True(t, n.Equal(NewVec3(0, 0.97014, -0.24254)))
+}
+
+func TestSphereIsAShape(t *testing.T) {
+	s := NewDefaultSphere()
+
+	var shape Shape = &s
+	require.Equal(t, "sphere_id", shape.Id())
+}
+
+func TestMovingShapeIsIntersectedWhereItIsAtTheTimeOfTheRay(t *testing.T) {
+	s := newTestShape()
+	s.SetMotion(NewKeyframe(0, NewTranslationMatrix(0, 0, 0)), NewKeyframe(1, NewTranslationMatrix(4, 0, 0)))
+
+	tests := []struct {
+		time   float64
+		origin Point3
+	}{
+		{-1, NewPoint3(0, 0, -5)},
+		{0, NewPoint3(0, 0, -5)},
+		{0.25, NewPoint3(-1, 0, -5)},
+		{1, NewPoint3(-4, 0, -5)},
+		{2, NewPoint3(-4, 0, -5)},
+	}
+
+	for _, test := range tests {
+		r := NewRayAtTime(NewPoint3(0, 0, -5), NewVec3(0, 0, 1), test.time)
+		IntersectWith(s, &r)
+
+		require.True(t, s.savedRay.origin.Equal(test.origin), "time %v: %v", test.time, s.savedRay.origin)
+		require.Equal(t, test.time, s.savedRay.time)
+	}
+}
+
+func TestNormalOnMovingShape(t *testing.T) {
+	s := newTestShape()
+	s.SetMotion(NewKeyframe(0, NewIdentityMatrix(4)), NewKeyframe(1, NewRotationZMatrix(math.Pi/2)))
+
+	require.True(t, NormalAt(s, NewPoint3(1, 0, 0), Intersection{}).Equal(NewVec3(1, 0, 0)))
+	n := normalAtTime(s, NewPoint3(COS45, COS45, 0), Intersection{}, 0.5)
+	require.True(t, n.Equal(NewVec3(COS45, COS45, 0)), n)
+}
+
+func TestSettingTransformStopsTheMotion(t *testing.T) {
+	s := newTestShape()
+	start, end := NewTranslationMatrix(1, 0, 0), NewTranslationMatrix(2, 0, 0)
+	s.SetMotion(NewKeyframe(1, end), NewKeyframe(0, start))
+
+	require.Len(t, s.Motion(), 2)
+	transform := s.Transform()
+	require.True(t, transform.Equal(start), "the earliest keyframe is the transform")
+
+	s.SetTransform(NewScalingMatrix(2, 2, 2))
+	require.Empty(t, s.Motion())
+	r := NewRayAtTime(NewPoint3(0, 0, -5), NewVec3(0, 0, 1), 1)
+	IntersectWith(s, &r)
+	require.True(t, s.savedRay.origin.Equal(NewPoint3(0, 0, -2.5)))
+}
@@ -4,28 +4,19 @@ import "math"
 
 // Unit sphere (radius == 1), with a center in (0,0,0)
 type Sphere struct {
-	id        string
-	origin    Tuple
-	transform Matrix
-	material  Material
+	shape
+	origin Point3
 }
 
 func NewSphere(id string, material Material) Sphere {
 	return Sphere{
-		id:        id,
-		origin:    NewPoint(0, 0, 0),
-		transform: *NewIdentityMatrix(4),
-		material:  material,
+		shape:  newShape(id, material),
+		origin: NewPoint3(0, 0, 0),
 	}
 }
 
 func NewDefaultSphere() Sphere {
-	return Sphere{
-		id:        "sphere_id",
-		origin:    NewPoint(0, 0, 0),
-		transform: *NewIdentityMatrix(4),
-		material:  NewDefaultMaterial(),
-	}
+	return NewSphere("sphere_id", NewDefaultMaterial())
 }
 
 func (s *Sphere) Equal(s2 *Sphere) bool {
@@ -34,25 +25,19 @@ func (s *Sphere) Equal(s2 *Sphere) bool {
 		s.transform.Equal(&s2.transform)
 }
 
-func (s *Sphere) Transform() Matrix {
-	return s.transform
+func (s *Sphere) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(s, r)
 }
 
-func (s *Sphere) SetTransform(m *Matrix) {
-	s.transform = *m
+func (s *Sphere) NormalAt(worldPoint Point3) Vec3 {
+	return NormalAt(s, worldPoint, Intersection{})
 }
 
 // Finds intersection of a ray going through the center of the sphere with a unit radius
-func (s *Sphere) IntersectWith(r *Ray) []Intersection {
-	// Inverse-transform the ray instead of transforming the sphere.
-	// It makes the math easier.
-	t := s.Transform()
-	transformedRay := r.ApplyTransform(t.Inverse())
-
-	sphereOrigin := NewPoint(0, 0, 0)
-	sphereToRay := transformedRay.origin.Sub(sphereOrigin)
-	a := transformedRay.direction.Dot(transformedRay.direction)
-	b := 2 * transformedRay.direction.Dot(sphereToRay)
+func (s *Sphere) localIntersectWith(r *Ray) []Intersection {
+	sphereToRay := r.origin.Sub(s.origin)
+	a := r.direction.Dot(r.direction)
+	b := 2 * r.direction.Dot(sphereToRay)
 	c := sphereToRay.Dot(sphereToRay) - 1
 	discriminant := b*b - 4*a*c
 
@@ -69,17 +54,10 @@ func (s *Sphere) IntersectWith(r *Ray) []Intersection {
 	}
 }
 
-func (s *Sphere) NormalAt(worldPoint Tuple) Tuple {
-	sphereCenter := NewPoint(0, 0, 0)
-
-	objectSpacePoint := s.transform.Inverse().MulTuple(worldPoint)
-	objectSpaceNormal := objectSpacePoint.Sub(sphereCenter)
-	// For usual point we could just multiply by a sphere's transformation matrix to
-	// transform vector from Object space to World space. But for normals it doesn't work,
-	// because it transforms them in undesired way (e.g. squishing normals along with squishing
-	// the object)
-	normalInWorldSpace := s.transform.Inverse().Transpose().MulTuple(objectSpaceNormal)
-	normalInWorldSpace = normalInWorldSpace.AsVector().Normalize()
+func (s *Sphere) Bounds() BoundingBox {
+	return NewBoundingBox(NewPoint3(-1, -1, -1), NewPoint3(1, 1, 1))
+}
 
-	return normalInWorldSpace
+func (s *Sphere) localNormalAt(point Point3, hit Intersection) Vec3 {
+	return point.Sub(s.origin)
 }
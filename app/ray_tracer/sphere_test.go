@@ -32,7 +32,7 @@ func TestSettingSphereTransformation(t *testing.T) {
 }
 
 func TestIntersectingScaledSphereWithRay(t *testing.T) {
-	origin, direction := NewPoint(0, 0, -5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 0, -5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 	s.SetTransform(NewScalingMatrix(2, 2, 2))
@@ -45,7 +45,7 @@ func TestIntersectingScaledSphereWithRay(t *testing.T) {
 }
 
 func TestIntersectingTranslatedSphereWithRay(t *testing.T) {
-	origin, direction := NewPoint(0, 0, -5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 0, -5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 	s.SetTransform(NewTranslationMatrix(5, 0, 0))
@@ -56,7 +56,7 @@ func TestIntersectingTranslatedSphereWithRay(t *testing.T) {
 }
 
 func TestRayIntersectsSphereAtTwoPoints(t *testing.T) {
-	origin, direction := NewPoint(0, 0, -5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 0, -5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 
@@ -68,7 +68,7 @@ func TestRayIntersectsSphereAtTwoPoints(t *testing.T) {
 }
 
 func TestRayIntersectsSphereAtATangent(t *testing.T) {
-	origin, direction := NewPoint(0, 1, -5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 1, -5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 
@@ -80,7 +80,7 @@ func TestRayIntersectsSphereAtATangent(t *testing.T) {
 }
 
 func TestRayMissesSphere(t *testing.T) {
-	origin, direction := NewPoint(0, 2, -5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 2, -5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 
@@ -91,7 +91,7 @@ func TestRayMissesSphere(t *testing.T) {
 
 // Ray extends *behind* the starting point, so we'll have 2 intersections
 func TestRayOriginatesInsideSphere(t *testing.T) {
-	origin, direction := NewPoint(0, 0, 0), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 0, 0), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 
@@ -103,7 +103,7 @@ func TestRayOriginatesInsideSphere(t *testing.T) {
 }
 
 func TestSphereCompletelyBehindRay(t *testing.T) {
-	origin, direction := NewPoint(0, 0, 5), NewVector(0, 0, 1)
+	origin, direction := NewPoint3(0, 0, 5), NewVec3(0, 0, 1)
 	r := NewRay(origin, direction)
 	s := NewDefaultSphere()
 
@@ -116,41 +116,41 @@ func TestSphereCompletelyBehindRay(t *testing.T) {
 
 func TestNormalOnSphereX(t *testing.T) {
 	s := NewDefaultSphere()
-	n := s.NormalAt(NewPoint(1, 0, 0))
+	n := s.NormalAt(NewPoint3(1, 0, 0))
 
-	expect := NewVector(1, 0, 0)
+	expect := NewVec3(1, 0, 0)
 	require.True(t, n.Equal(expect))
 }
 
 func TestNormalOnSphereY(t *testing.T) {
 	s := NewDefaultSphere()
-	n := s.NormalAt(NewPoint(0, 1, 0))
+	n := s.NormalAt(NewPoint3(0, 1, 0))
 
-	expect := NewVector(0, 1, 0)
+	expect := NewVec3(0, 1, 0)
 	require.True(t, n.Equal(expect))
 
 }
 func TestNormalOnSphereZ(t *testing.T) {
 	s := NewDefaultSphere()
-	n := s.NormalAt(NewPoint(0, 0, 1))
+	n := s.NormalAt(NewPoint3(0, 0, 1))
 
-	expect := NewVector(0, 0, 1)
+	expect := NewVec3(0, 0, 1)
 	require.True(t, n.Equal(expect))
 }
 
 func TestNormalOnSphereNonAxial(t *testing.T) {
 	s := NewDefaultSphere()
 	v := math.Sqrt(3) / 3.0
-	n := s.NormalAt(NewPoint(v, v, v))
+	n := s.NormalAt(NewPoint3(v, v, v))
 
-	expect := NewVector(v, v, v)
+	expect := NewVec3(v, v, v)
 	require.True(t, n.Equal(expect))
 }
 
 func TestNormalVectorsAreAlwaysNormalized(t *testing.T) {
 	s := NewDefaultSphere()
 	v := math.Sqrt(3) / 3.0
-	n := s.NormalAt(NewPoint(v, v, v))
+	n := s.NormalAt(NewPoint3(v, v, v))
 
 	expect := n.Normalize()
 	require.True(t, n.Equal(expect))
@@ -159,9 +159,9 @@ func TestNormalVectorsAreAlwaysNormalized(t *testing.T) {
 func TestComputingNormalOnTranslatedSphere(t *testing.T) {
 	s := NewDefaultSphere()
 	s.SetTransform(NewTranslationMatrix(0, 1, 0))
-	n := s.NormalAt(NewPoint(0, 1.70711, -0.70711))
+	n := s.NormalAt(NewPoint3(0, 1.70711, -0.70711))
 
-	expect := NewVector(0, 0.70711, -0.70711)
+	expect := NewVec3(0, 0.70711, -0.70711)
 	require.True(t, n.Equal(expect))
 }
 
@@ -169,8 +169,8 @@ func TestComputingNormalOnTransformedSphere(t *testing.T) {
 	s := NewDefaultSphere()
 	transform := NewIdentityMatrix(4).RotateZ(math.Pi/5).Scale(1, 0.5, 1)
 	s.SetTransform(transform)
-	n := s.NormalAt(NewPoint(0, COS45, -COS45))
+	n := s.NormalAt(NewPoint3(0, COS45, -COS45))
 
-	expect := NewVector(0, 0.97014, -0.24254)
+	expect := NewVec3(0, 0.97014, -0.24254)
 	require.True(t, n.Equal(expect))
 }
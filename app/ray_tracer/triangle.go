@@ -0,0 +1,152 @@
+package ray_tracer
+
+import "math"
+
+// Flat triangle defined by 3 points. Edges and normal are precomputed, because
+// they never change and are used for every intersection
+type Triangle struct {
+	shape
+	p1     Point3
+	p2     Point3
+	p3     Point3
+	e1     Vec3
+	e2     Vec3
+	normal Vec3
+}
+
+func NewTriangle(id string, material Material, p1, p2, p3 Point3) Triangle {
+	e1, e2 := p2.Sub(p1), p3.Sub(p1)
+	return Triangle{
+		shape:  newShape(id, material),
+		p1:     p1,
+		p2:     p2,
+		p3:     p3,
+		e1:     e1,
+		e2:     e2,
+		normal: e2.Cross(e1).Normalize(),
+	}
+}
+
+func NewDefaultTriangle(p1, p2, p3 Point3) Triangle {
+	return NewTriangle("triangle_id", NewDefaultMaterial(), p1, p2, p3)
+}
+
+func (tri *Triangle) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(tri, r)
+}
+
+func (tri *Triangle) NormalAt(worldPoint Point3) Vec3 {
+	return NormalAt(tri, worldPoint, Intersection{})
+}
+
+func (tri *Triangle) localIntersectWith(r *Ray) []Intersection {
+	t, u, v, ok := intersectTriangle(r, tri.p1, tri.e1, tri.e2)
+	if !ok {
+		return []Intersection{}
+	}
+	return []Intersection{NewIntersectionWithUV(t, tri, u, v)}
+}
+
+func (tri *Triangle) Bounds() BoundingBox {
+	return NewEmptyBoundingBox().AddPoint(tri.p1).AddPoint(tri.p2).AddPoint(tri.p3)
+}
+
+func (tri *Triangle) localNormalAt(point Point3, hit Intersection) Vec3 {
+	return tri.normal
+}
+
+// Möller–Trumbore algorithm. Besides the time of the intersection returns u and v -
+// barycentric coordinates of the intersection point relative to the triangle's corners
+func intersectTriangle(r *Ray, p1 Point3, e1, e2 Vec3) (t, u, v float64, ok bool) {
+	dirCrossE2 := r.direction.Cross(e2)
+	det := e1.Dot(dirCrossE2)
+	// ray is parallel to the triangle's plane
+	if math.Abs(det) < EPSILON {
+		return 0, 0, 0, false
+	}
+
+	f := 1.0 / det
+	p1ToOrigin := r.origin.Sub(p1)
+	u = f * p1ToOrigin.Dot(dirCrossE2)
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+
+	originCrossE1 := p1ToOrigin.Cross(e1)
+	v = f * r.direction.Dot(originCrossE1)
+	if v < 0 || (u+v) > 1 {
+		return 0, 0, 0, false
+	}
+
+	t = f * e2.Dot(originCrossE1)
+	return t, u, v, true
+}
+
+// Triangle with normals in each of its vertices. Normal at any point of the triangle is
+// interpolated from the vertex normals, which makes a mesh of such triangles look smooth
+type SmoothTriangle struct {
+	shape
+	p1 Point3
+	p2 Point3
+	p3 Point3
+	n1 Vec3
+	n2 Vec3
+	n3 Vec3
+	e1 Vec3
+	e2 Vec3
+}
+
+func NewSmoothTriangle(id string, material Material, p1, p2, p3 Point3, n1, n2, n3 Vec3) SmoothTriangle {
+	return SmoothTriangle{
+		shape: newShape(id, material),
+		p1:    p1,
+		p2:    p2,
+		p3:    p3,
+		n1:    n1,
+		n2:    n2,
+		n3:    n3,
+		e1:    p2.Sub(p1),
+		e2:    p3.Sub(p1),
+	}
+}
+
+func NewDefaultSmoothTriangle(p1, p2, p3 Point3, n1, n2, n3 Vec3) SmoothTriangle {
+	return NewSmoothTriangle("smooth_triangle_id", NewDefaultMaterial(), p1, p2, p3, n1, n2, n3)
+}
+
+func (tri *SmoothTriangle) IntersectWith(r *Ray) []Intersection {
+	return IntersectWith(tri, r)
+}
+
+// There is no hit to take u and v from, so they are found from the position of the point
+func (tri *SmoothTriangle) NormalAt(worldPoint Point3) Vec3 {
+	u, v := tri.uvAt(worldToObject(tri, worldPoint, 0))
+	return NormalAt(tri, worldPoint, NewIntersectionWithUV(0, tri, u, v))
+}
+
+// Barycentric coordinates of the point lying on the triangle: point = p1 + u*e1 + v*e2
+func (tri *SmoothTriangle) uvAt(point Point3) (u, v float64) {
+	p := point.Sub(tri.p1)
+	d11, d12, d22 := tri.e1.Dot(tri.e1), tri.e1.Dot(tri.e2), tri.e2.Dot(tri.e2)
+	dp1, dp2 := p.Dot(tri.e1), p.Dot(tri.e2)
+	denominator := d11*d22 - d12*d12
+	return (d22*dp1 - d12*dp2) / denominator, (d11*dp2 - d12*dp1) / denominator
+}
+
+func (tri *SmoothTriangle) localIntersectWith(r *Ray) []Intersection {
+	t, u, v, ok := intersectTriangle(r, tri.p1, tri.e1, tri.e2)
+	if !ok {
+		return []Intersection{}
+	}
+	return []Intersection{NewIntersectionWithUV(t, tri, u, v)}
+}
+
+func (tri *SmoothTriangle) Bounds() BoundingBox {
+	return NewEmptyBoundingBox().AddPoint(tri.p1).AddPoint(tri.p2).AddPoint(tri.p3)
+}
+
+func (tri *SmoothTriangle) localNormalAt(point Point3, hit Intersection) Vec3 {
+	return tri.n2.Mul(hit.u).
+		Add(tri.n3.Mul(hit.v)).
+		Add(tri.n1.Mul(1 - hit.u - hit.v))
+}
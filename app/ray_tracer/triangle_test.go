@@ -0,0 +1,117 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTriangle() Triangle {
+	return NewDefaultTriangle(NewPoint3(0, 1, 0), NewPoint3(-1, 0, 0), NewPoint3(1, 0, 0))
+}
+
+func newTestSmoothTriangle() SmoothTriangle {
+	p1, p2, p3 := NewPoint3(0, 1, 0), NewPoint3(-1, 0, 0), NewPoint3(1, 0, 0)
+	n1, n2, n3 := NewVec3(0, 1, 0), NewVec3(-1, 0, 0), NewVec3(1, 0, 0)
+	return NewDefaultSmoothTriangle(p1, p2, p3, n1, n2, n3)
+}
+
+func TestConstructingTriangle(t *testing.T) {
+	tri := newTestTriangle()
+
+	require.True(t, tri.e1.Equal(NewVec3(-1, -1, 0)))
+	require.True(t, tri.e2.Equal(NewVec3(1, -1, 0)))
+	require.True(t, tri.normal.Equal(NewVec3(0, 0, -1)))
+}
+
+func TestNormalOnTriangleIsConstant(t *testing.T) {
+	tri := newTestTriangle()
+
+	n1 := tri.localNormalAt(NewPoint3(0, 0.5, 0), Intersection{})
+	n2 := tri.localNormalAt(NewPoint3(-0.5, 0.75, 0), Intersection{})
+	n3 := tri.localNormalAt(NewPoint3(0.5, 0.25, 0), Intersection{})
+
+	require.True(t, n1.Equal(tri.normal))
+	require.True(t, n2.Equal(tri.normal))
+	require.True(t, n3.Equal(tri.normal))
+}
+
+func TestIntersectingRayParallelToTriangle(t *testing.T) {
+	tri := newTestTriangle()
+	r := NewRay(NewPoint3(0, -1, -2), NewVec3(0, 1, 0))
+
+	xs := tri.localIntersectWith(&r)
+
+	require.Len(t, xs, 0)
+}
+
+func TestRayMissesTriangleEdges(t *testing.T) {
+	tri := newTestTriangle()
+	origins := []Point3{NewPoint3(1, 1, -2), NewPoint3(-1, 1, -2), NewPoint3(0, -1, -2)}
+
+	for _, origin := range origins {
+		r := NewRay(origin, NewVec3(0, 0, 1))
+
+		xs := tri.localIntersectWith(&r)
+
+		require.Len(t, xs, 0, "ray from %v", origin)
+	}
+}
+
+func TestRayStrikesTriangle(t *testing.T) {
+	tri := newTestTriangle()
+	r := NewRay(NewPoint3(0, 0.5, -2), NewVec3(0, 0, 1))
+
+	xs := tri.localIntersectWith(&r)
+
+	require.Len(t, xs, 1)
+	require.EqualValues(t, 2, xs[0].time)
+}
+
+func TestIntersectionCanEncapsulateUAndV(t *testing.T) {
+	tri := newTestTriangle()
+
+	i := NewIntersectionWithUV(3.5, &tri, 0.2, 0.4)
+
+	require.EqualValues(t, 0.2, i.u)
+	require.EqualValues(t, 0.4, i.v)
+}
+
+func TestIntersectionWithSmoothTriangleStoresUAndV(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	r := NewRay(NewPoint3(-0.2, 0.3, -2), NewVec3(0, 0, 1))
+
+	xs := tri.localIntersectWith(&r)
+
+	require.Len(t, xs, 1)
+	require.InDelta(t, 0.45, xs[0].u, EPSILON)
+	require.InDelta(t, 0.25, xs[0].v, EPSILON)
+}
+
+func TestSmoothTriangleUsesUAndVToInterpolateNormal(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	i := NewIntersectionWithUV(1, &tri, 0.45, 0.25)
+
+	n := NormalAt(&tri, NewPoint3(0, 0, 0), i)
+
+	require.True(t, n.Equal(NewVec3(-0.5547, 0.83205, 0)), "normal %v", n)
+}
+
+func TestSmoothTriangleFindsUAndVOfThePoint(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	tri.SetTransform(NewTranslationMatrix(0, 0, 5))
+
+	n := tri.NormalAt(NewPoint3(-0.2, 0.3, 5))
+
+	require.True(t, n.Equal(NewVec3(-0.5547, 0.83205, 0)), "normal %v", n)
+}
+
+func TestPreparingNormalOnSmoothTriangle(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	i := NewIntersectionWithUV(1, &tri, 0.45, 0.25)
+	r := NewRay(NewPoint3(-0.2, 0.3, -2), NewVec3(0, 0, 1))
+
+	comps := PrepareIntersectionComputations(i, r, []Intersection{i})
+
+	require.True(t, comps.objectNormalv.Equal(NewVec3(-0.5547, 0.83205, 0)))
+}
@@ -0,0 +1,161 @@
+package ray_tracer
+
+import "math"
+
+// Point in 2D texture coordinates
+type UV struct {
+	u, v float64
+}
+
+func NewUV(u, v float64) UV {
+	return UV{u: u, v: v}
+}
+
+// Maps a point on the surface of a shape (in the pattern space) onto 2D texture
+// coordinates. Both u and v are in [0, 1]
+type UVMapping func(p Point3) (u, v float64)
+
+// Maps the unit sphere. u goes around Y axis, v goes from the south pole to the north one
+func SphericalMap(p Point3) (u, v float64) {
+	// azimuthal angle in (-pi, pi]
+	theta := math.Atan2(p.x, p.z)
+	radius := math.Sqrt(p.x*p.x + p.y*p.y + p.z*p.z)
+	// polar angle in [0, pi]
+	phi := math.Acos(p.y / radius)
+
+	rawU := theta / (2 * math.Pi)
+	// u increases counterclockwise, when viewed from above
+	u = 1 - (rawU + 0.5)
+	// north pole has v == 1
+	v = 1 - phi/math.Pi
+	return u, v
+}
+
+func fraction(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+// Maps XZ plane, the texture repeats every unit
+func PlanarMap(p Point3) (u, v float64) {
+	return fraction(p.x), fraction(p.z)
+}
+
+// Maps the unit cylinder. u goes around Y axis like in SphericalMap, v repeats every unit of Y
+func CylindricalMap(p Point3) (u, v float64) {
+	theta := math.Atan2(p.x, p.z)
+	rawU := theta / (2 * math.Pi)
+	return 1 - (rawU + 0.5), fraction(p.y)
+}
+
+type CubeFace int
+
+const (
+	CUBE_FACE_LEFT CubeFace = iota
+	CUBE_FACE_RIGHT
+	CUBE_FACE_FRONT
+	CUBE_FACE_BACK
+	CUBE_FACE_UP
+	CUBE_FACE_DOWN
+)
+
+// Face of the unit cube the point belongs to, it's the one along the biggest coordinate
+func CubeFaceOf(p Point3) CubeFace {
+	coord := math.Max(math.Abs(p.x), math.Max(math.Abs(p.y), math.Abs(p.z)))
+	switch coord {
+	case p.x:
+		return CUBE_FACE_RIGHT
+	case -p.x:
+		return CUBE_FACE_LEFT
+	case p.y:
+		return CUBE_FACE_UP
+	case -p.y:
+		return CUBE_FACE_DOWN
+	case p.z:
+		return CUBE_FACE_FRONT
+	default:
+		return CUBE_FACE_BACK
+	}
+}
+
+// Maps the unit cube. Every face is mapped separately, as if looking at it from
+// the outside with Y axis (or -Z for the top and bottom faces) pointing up
+func CubeMap(p Point3) (face CubeFace, u, v float64) {
+	face = CubeFaceOf(p)
+	switch face {
+	case CUBE_FACE_FRONT:
+		u, v = math.Mod(p.x+1, 2)/2, math.Mod(p.y+1, 2)/2
+	case CUBE_FACE_BACK:
+		u, v = math.Mod(1-p.x, 2)/2, math.Mod(p.y+1, 2)/2
+	case CUBE_FACE_LEFT:
+		u, v = math.Mod(p.z+1, 2)/2, math.Mod(p.y+1, 2)/2
+	case CUBE_FACE_RIGHT:
+		u, v = math.Mod(1-p.z, 2)/2, math.Mod(p.y+1, 2)/2
+	case CUBE_FACE_UP:
+		u, v = math.Mod(p.x+1, 2)/2, math.Mod(1-p.z, 2)/2
+	case CUBE_FACE_DOWN:
+		u, v = math.Mod(p.x+1, 2)/2, math.Mod(p.z+1, 2)/2
+	}
+	return face, u, v
+}
+
+// 2D pattern, which is put onto the surface with a UV mapping
+type UVPattern interface {
+	uvColorAt(u, v float64) Color
+}
+
+// 2D checkerboard with width squares along u and height squares along v
+type UVCheckerPattern struct {
+	width  int
+	height int
+	a      Color
+	b      Color
+}
+
+func NewUVCheckerPattern(width, height int, a, b Color) *UVCheckerPattern {
+	return &UVCheckerPattern{width: width, height: height, a: a, b: b}
+}
+
+func (p *UVCheckerPattern) uvColorAt(u, v float64) Color {
+	if isEven(math.Floor(u*float64(p.width)) + math.Floor(v*float64(p.height))) {
+		return p.a
+	}
+	return p.b
+}
+
+// Puts a 2D pattern onto a shape using the mapping
+type TextureMapPattern struct {
+	pattern
+	uvPattern UVPattern
+	mapping   UVMapping
+}
+
+func NewTextureMapPattern(uvPattern UVPattern, mapping UVMapping) *TextureMapPattern {
+	return &TextureMapPattern{pattern: newPattern(), uvPattern: uvPattern, mapping: mapping}
+}
+
+func (p *TextureMapPattern) localColorAt(point Point3) Color {
+	u, v := p.mapping(point)
+	return p.uvPattern.uvColorAt(u, v)
+}
+
+// Puts a separate 2D pattern on every face of the unit cube, see CubeMap
+type CubeMapPattern struct {
+	pattern
+	faces [6]UVPattern
+}
+
+func NewCubeMapPattern(left, front, right, back, up, down UVPattern) *CubeMapPattern {
+	p := &CubeMapPattern{pattern: newPattern()}
+	p.faces[CUBE_FACE_LEFT] = left
+	p.faces[CUBE_FACE_FRONT] = front
+	p.faces[CUBE_FACE_RIGHT] = right
+	p.faces[CUBE_FACE_BACK] = back
+	p.faces[CUBE_FACE_UP] = up
+	p.faces[CUBE_FACE_DOWN] = down
+	return p
+}
+
+func (p *CubeMapPattern) localColorAt(point Point3) Color {
+	face, u, v := CubeMap(point)
+	return p.faces[face].uvColorAt(u, v)
+}
@@ -0,0 +1,174 @@
+package ray_tracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUVCheckerPattern(t *testing.T) {
+	p := NewUVCheckerPattern(2, 2, BLACK, WHITE)
+
+	for _, tc := range []struct {
+		u, v   float64
+		expect Color
+	}{
+		{0, 0, BLACK},
+		{0.5, 0, WHITE},
+		{0, 0.5, WHITE},
+		{0.5, 0.5, BLACK},
+		{1, 1, BLACK},
+	} {
+		require.Equal(t, tc.expect, p.uvColorAt(tc.u, tc.v), "u: %v, v: %v", tc.u, tc.v)
+	}
+}
+
+func TestSphericalMappingOnAPoint(t *testing.T) {
+	for _, tc := range []struct {
+		point Point3
+		u, v  float64
+	}{
+		{NewPoint3(0, 0, -1), 0, 0.5},
+		{NewPoint3(1, 0, 0), 0.25, 0.5},
+		{NewPoint3(0, 0, 1), 0.5, 0.5},
+		{NewPoint3(-1, 0, 0), 0.75, 0.5},
+		{NewPoint3(0, 1, 0), 0.5, 1},
+		{NewPoint3(0, -1, 0), 0.5, 0},
+		{NewPoint3(COS45, COS45, 0), 0.25, 0.75},
+	} {
+		u, v := SphericalMap(tc.point)
+
+		require.InDelta(t, tc.u, u, EPSILON, tc.point)
+		require.InDelta(t, tc.v, v, EPSILON, tc.point)
+	}
+}
+
+func TestPlanarMappingOnAPoint(t *testing.T) {
+	for _, tc := range []struct {
+		point Point3
+		u, v  float64
+	}{
+		{NewPoint3(0.25, 0, 0.5), 0.25, 0.5},
+		{NewPoint3(0.25, 0, -0.25), 0.25, 0.75},
+		{NewPoint3(0.25, 0.5, -0.25), 0.25, 0.75},
+		{NewPoint3(1.25, 0, 0.5), 0.25, 0.5},
+		{NewPoint3(0.25, 0, -1.75), 0.25, 0.25},
+		{NewPoint3(1, 0, -1), 0, 0},
+		{NewPoint3(0, 0, 0), 0, 0},
+	} {
+		u, v := PlanarMap(tc.point)
+
+		require.InDelta(t, tc.u, u, EPSILON, tc.point)
+		require.InDelta(t, tc.v, v, EPSILON, tc.point)
+	}
+}
+
+func TestCylindricalMappingOnAPoint(t *testing.T) {
+	for _, tc := range []struct {
+		point Point3
+		u, v  float64
+	}{
+		{NewPoint3(0, 0, -1), 0, 0},
+		{NewPoint3(0, 0.5, -1), 0, 0.5},
+		{NewPoint3(0, 1, -1), 0, 0},
+		{NewPoint3(0.70711, 0.5, -0.70711), 0.125, 0.5},
+		{NewPoint3(1, 0.5, 0), 0.25, 0.5},
+		{NewPoint3(0.70711, 0.5, 0.70711), 0.375, 0.5},
+		{NewPoint3(0, -0.25, 1), 0.5, 0.75},
+		{NewPoint3(-0.70711, 0.5, 0.70711), 0.625, 0.5},
+		{NewPoint3(-1, 1.25, 0), 0.75, 0.25},
+		{NewPoint3(-0.70711, 0.5, -0.70711), 0.875, 0.5},
+	} {
+		u, v := CylindricalMap(tc.point)
+
+		require.InDelta(t, tc.u, u, EPSILON, tc.point)
+		require.InDelta(t, tc.v, v, EPSILON, tc.point)
+	}
+}
+
+func TestUsingTextureMapPatternWithSphericalMap(t *testing.T) {
+	p := NewTextureMapPattern(NewUVCheckerPattern(16, 8, BLACK, WHITE), SphericalMap)
+
+	for _, tc := range []struct {
+		point  Point3
+		expect Color
+	}{
+		{NewPoint3(0.4315, 0.4670, 0.7719), WHITE},
+		{NewPoint3(-0.9654, 0.2552, -0.0534), BLACK},
+		{NewPoint3(0.1039, 0.7090, 0.6975), WHITE},
+		{NewPoint3(-0.4986, -0.7856, -0.3663), BLACK},
+		{NewPoint3(-0.0317, -0.9395, 0.3411), BLACK},
+		{NewPoint3(0.4809, -0.7721, 0.4154), BLACK},
+		{NewPoint3(0.0285, -0.9612, -0.2745), BLACK},
+		{NewPoint3(-0.5734, -0.2162, -0.7903), WHITE},
+		{NewPoint3(0.7688, -0.1470, 0.6223), BLACK},
+		{NewPoint3(-0.7652, 0.2175, 0.6060), BLACK},
+	} {
+		require.Equal(t, tc.expect, PatternAt(p, tc.point), tc.point)
+	}
+}
+
+func TestIdentifyingTheFaceOfACubeFromAPoint(t *testing.T) {
+	for _, tc := range []struct {
+		point Point3
+		face  CubeFace
+	}{
+		{NewPoint3(-1, 0.5, -0.25), CUBE_FACE_LEFT},
+		{NewPoint3(1.1, -0.75, 0.8), CUBE_FACE_RIGHT},
+		{NewPoint3(0.1, 0.6, 0.9), CUBE_FACE_FRONT},
+		{NewPoint3(-0.7, 0, -2), CUBE_FACE_BACK},
+		{NewPoint3(0.5, 1, 0.9), CUBE_FACE_UP},
+		{NewPoint3(-0.2, -1.3, 1.1), CUBE_FACE_DOWN},
+	} {
+		require.Equal(t, tc.face, CubeFaceOf(tc.point), tc.point)
+	}
+}
+
+func TestUVMappingOfCubeFaces(t *testing.T) {
+	for _, tc := range []struct {
+		point Point3
+		face  CubeFace
+		u, v  float64
+	}{
+		{NewPoint3(-0.5, 0.5, 1), CUBE_FACE_FRONT, 0.25, 0.75},
+		{NewPoint3(0.5, -0.5, 1), CUBE_FACE_FRONT, 0.75, 0.25},
+		{NewPoint3(0.5, 0.5, -1), CUBE_FACE_BACK, 0.25, 0.75},
+		{NewPoint3(-0.5, -0.5, -1), CUBE_FACE_BACK, 0.75, 0.25},
+		{NewPoint3(-1, 0.5, -0.5), CUBE_FACE_LEFT, 0.25, 0.75},
+		{NewPoint3(-1, -0.5, 0.5), CUBE_FACE_LEFT, 0.75, 0.25},
+		{NewPoint3(1, 0.5, 0.5), CUBE_FACE_RIGHT, 0.25, 0.75},
+		{NewPoint3(1, -0.5, -0.5), CUBE_FACE_RIGHT, 0.75, 0.25},
+		{NewPoint3(-0.5, 1, -0.5), CUBE_FACE_UP, 0.25, 0.75},
+		{NewPoint3(0.5, 1, 0.5), CUBE_FACE_UP, 0.75, 0.25},
+		{NewPoint3(-0.5, -1, 0.5), CUBE_FACE_DOWN, 0.25, 0.75},
+		{NewPoint3(0.5, -1, -0.5), CUBE_FACE_DOWN, 0.75, 0.25},
+	} {
+		face, u, v := CubeMap(tc.point)
+
+		require.Equal(t, tc.face, face, tc.point)
+		require.InDelta(t, tc.u, u, EPSILON, tc.point)
+		require.InDelta(t, tc.v, v, EPSILON, tc.point)
+	}
+}
+
+func TestCubeMapPatternUsesPatternOfTheFace(t *testing.T) {
+	solid := func(c Color) UVPattern { return NewUVCheckerPattern(1, 1, c, c) }
+	p := NewCubeMapPattern(solid(RED), solid(GREEN), solid(BLUE), solid(YELLOW), solid(WHITE), solid(BLACK))
+
+	require.Equal(t, RED, PatternAt(p, NewPoint3(-1, 0.3, 0.2)))
+	require.Equal(t, GREEN, PatternAt(p, NewPoint3(0.3, 0.2, 1)))
+	require.Equal(t, BLUE, PatternAt(p, NewPoint3(1, 0.3, 0.2)))
+	require.Equal(t, YELLOW, PatternAt(p, NewPoint3(0.3, 0.2, -1)))
+	require.Equal(t, WHITE, PatternAt(p, NewPoint3(0.3, 1, 0.2)))
+	require.Equal(t, BLACK, PatternAt(p, NewPoint3(0.3, -1, 0.2)))
+}
+
+func TestTextureMapPatternOnTransformedSphere(t *testing.T) {
+	s := NewDefaultSphere()
+	s.SetTransform(NewScalingMatrix(2, 2, 2))
+	p := NewTextureMapPattern(NewUVCheckerPattern(2, 1, BLACK, WHITE), SphericalMap)
+
+	// u == 0.25 and 0.75 respectively
+	require.Equal(t, BLACK, PatternAtShape(p, &s, NewPoint3(2, 0, 0)))
+	require.Equal(t, WHITE, PatternAtShape(p, &s, NewPoint3(-2, 0, 0)))
+}
@@ -0,0 +1,75 @@
+package ray_tracer
+
+import "math"
+
+// Vector in 3D space. Unlike Tuple it can't be mixed up with a point: only
+// meaningful operations are defined, so misuse doesn't compile
+type Vec3 struct {
+	x float64
+	y float64
+	z float64
+}
+
+func NewVec3(x, y, z float64) Vec3 {
+	return Vec3{x, y, z}
+}
+
+func (a Vec3) Equal(b Vec3) bool {
+	return equal_fp(a.x, b.x) && equal_fp(a.y, b.y) && equal_fp(a.z, b.z)
+}
+
+func (a Vec3) Add(b Vec3) Vec3 {
+	return Vec3{a.x + b.x, a.y + b.y, a.z + b.z}
+}
+
+func (a Vec3) Sub(b Vec3) Vec3 {
+	return Vec3{a.x - b.x, a.y - b.y, a.z - b.z}
+}
+
+func (v Vec3) Negate() Vec3 {
+	return Vec3{-v.x, -v.y, -v.z}
+}
+
+func (v Vec3) Mul(c float64) Vec3 {
+	return Vec3{v.x * c, v.y * c, v.z * c}
+}
+
+func (v Vec3) Div(c float64) Vec3 {
+	if equal_fp(c, 0) {
+		panic("Can't divide by zero!")
+	}
+	return Vec3{v.x / c, v.y / c, v.z / c}
+}
+
+func (v Vec3) Magnitude() float64 {
+	return math.Sqrt(v.x*v.x + v.y*v.y + v.z*v.z)
+}
+
+func (v Vec3) Normalize() Vec3 {
+	m := v.Magnitude()
+	return Vec3{v.x / m, v.y / m, v.z / m}
+}
+
+// See Tuple.Dot
+func (a Vec3) Dot(b Vec3) float64 {
+	return a.x*b.x + a.y*b.y + a.z*b.z
+}
+
+func (a Vec3) Cross(b Vec3) Vec3 {
+	return Vec3{a.y*b.z - a.z*b.y, a.z*b.x - a.x*b.z, a.x*b.y - a.y*b.x}
+}
+
+func (v Vec3) ReflectAround(normal Vec3) Vec3 {
+	return v.Sub(normal.Mul(2 * v.Dot(normal)))
+}
+
+func (v Vec3) ToTuple() Tuple {
+	return NewVector(v.x, v.y, v.z)
+}
+
+func (t Tuple) ToVec3() Vec3 {
+	if !t.IsVector() {
+		panic("Only a vector can be converted to Vec3!")
+	}
+	return Vec3{t.x, t.y, t.z}
+}
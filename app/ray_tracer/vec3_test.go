@@ -0,0 +1,61 @@
+package ray_tracer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVec3Arithmetic(t *testing.T) {
+	a := NewVec3(1, -2, 3)
+	b := NewVec3(2, 3, 4)
+
+	require.Equal(t, NewVec3(3, 1, 7), a.Add(b))
+	require.Equal(t, NewVec3(-1, -5, -1), a.Sub(b))
+	require.Equal(t, NewVec3(-1, 2, -3), a.Negate())
+	require.Equal(t, NewVec3(3.5, -7, 10.5), a.Mul(3.5))
+	require.Equal(t, NewVec3(0.5, -1, 1.5), a.Div(2))
+	require.Panics(t, func() { a.Div(0) })
+}
+
+func TestVec3MagnitudeAndNormalization(t *testing.T) {
+	v := NewVec3(1, 2, 3)
+
+	require.Equal(t, math.Sqrt(14), v.Magnitude())
+	require.True(t, v.Normalize().Equal(NewVec3(0.26726, 0.53452, 0.80178)))
+	require.True(t, equal_fp(1, v.Normalize().Magnitude()))
+}
+
+func TestVec3DotAndCrossProducts(t *testing.T) {
+	a := NewVec3(1, 2, 3)
+	b := NewVec3(2, 3, 4)
+
+	require.Equal(t, 20.0, a.Dot(b))
+	require.Equal(t, NewVec3(-1, 2, -1), a.Cross(b))
+	require.Equal(t, NewVec3(1, -2, 1), b.Cross(a))
+}
+
+func TestReflectingVec3ApproachingAt45Degrees(t *testing.T) {
+	v := NewVec3(1, -1, 0)
+	n := NewVec3(0, 1, 0)
+
+	require.True(t, v.ReflectAround(n).Equal(NewVec3(1, 1, 0)))
+}
+
+func TestConvertingVec3ToTupleAndBack(t *testing.T) {
+	v := NewVec3(1, 2, 3)
+
+	require.True(t, v.ToTuple().Equal(NewVector(1, 2, 3)))
+	require.Equal(t, v, NewVector(1, 2, 3).ToVec3())
+	require.Panics(t, func() { NewPoint(1, 2, 3).ToVec3() })
+}
+
+func TestTranslationDoesNotAffectVec3(t *testing.T) {
+	m := NewTranslationMatrix(5, -3, 2)
+	m4 := m.ToMat4()
+	v := NewVec3(-3, 4, 5)
+
+	require.Equal(t, v, m.MulVec(v))
+	require.Equal(t, v, m4.MulVec(v))
+}
@@ -6,17 +6,35 @@ import (
 )
 
 type SceneObject interface{}
-type World map[string]SceneObject
 
-func NewWorld() World {
-	return World{}
+// Default number of times a ray may bounce between reflective surfaces
+const MAX_REFLECTION_DEPTH = 5
+
+// Shapes and lights of the scene by their names
+type World struct {
+	objects map[string]SceneObject
+	// Lights are needed for every shaded point, so they are kept apart sorted by their names
+	lightNames []string
+	lights     []Light
+	// Built by BuildBVH and dropped whenever the shapes are added, replaced, removed or moved
+	bvh *worldBVH
+}
+
+// World's bounding volume hierarchy together with its stats
+type worldBVH struct {
+	bvh   *bvh
+	stats BVHStats
+}
+
+func NewWorld() *World {
+	return &World{objects: map[string]SceneObject{}}
 }
 
 // Default world is hardcoded and contains 2 spheres "s1" and "s2" and point light "light".
 // Spheres' origins in the (0,0,0) and s2 is 2 times smaller than s1. Hence s1 may be conidered
 // as an outer sphere, and s1 is an inner sphere
-func NewDefaultWorld() World {
-	light := NewPointLight(NewPoint(-10, 10, -10), WHITE)
+func NewDefaultWorld() *World {
+	light := NewPointLight(NewPoint3(-10, 10, -10), WHITE)
 
 	lightGreen := NewColor(0.8, 1, 0.6)
 	m := NewDefaultMaterial()
@@ -29,29 +47,86 @@ func NewDefaultWorld() World {
 	s2 := NewDefaultSphere()
 	s2.SetTransform(NewScalingMatrix(0.5, 0.5, 0.5))
 
-	return World{"light": &light, "s1": &s1, "s2": &s2}
+	w := NewWorld()
+	w.Add("light", light)
+	w.Add("s1", &s1)
+	w.Add("s2", &s2)
+	return w
 }
 
-func (w World) Light() PointLight {
-	object, ok := w["light"]
-	if !ok {
-		panic("World has no light in it =(")
+// Adds the object or replaces the one with the same name. Only shapes and lights
+// may be added, anything else can't be drawn
+func (w *World) Add(name string, obj SceneObject) {
+	switch obj.(type) {
+	case Shape, Light:
+	default:
+		panic(fmt.Sprintf("Object %q of type %T is neither a shape nor a light!", name, obj))
+	}
+
+	w.Remove(name)
+	switch obj := obj.(type) {
+	case Shape:
+		obj.setWorld(w)
+		w.bvh = nil
+	case Light:
+		i := sort.SearchStrings(w.lightNames, name)
+		w.lightNames = append(w.lightNames[:i], append([]string{name}, w.lightNames[i:]...)...)
+		w.lights = append(w.lights[:i], append([]Light{obj}, w.lights[i:]...)...)
 	}
+	w.objects[name] = obj
+}
+
+func (w *World) Remove(name string) {
+	switch obj := w.objects[name].(type) {
+	case Shape:
+		obj.setWorld(nil)
+		w.bvh = nil
+	case Light:
+		// lights are not in the hierarchy, so it stays valid
+		i := sort.SearchStrings(w.lightNames, name)
+		w.lightNames = append(w.lightNames[:i], w.lightNames[i+1:]...)
+		w.lights = append(w.lights[:i], w.lights[i+1:]...)
+	}
+	delete(w.objects, name)
+}
+
+// Returns nil if there is no object with such name
+func (w *World) Object(name string) SceneObject {
+	return w.objects[name]
+}
+
+// Number of objects, including the lights
+func (w *World) Len() int {
+	return len(w.objects)
+}
 
-	light, ok := object.(*PointLight)
+// The light stored under "light" key, whatever kind it is. Use Lights to get all of them
+func (w *World) Light() Light {
+	light, ok := w.objects["light"].(Light)
 	if !ok {
-		panic("The world is corrupted! world[\"light\"] type is not PointLight!")
+		panic("World has no light in it =(")
 	}
+	return light
+}
+
+func (w *World) SetLight(pl PointLight) {
+	w.Add("light", pl)
+}
 
-	return *light
+// Lights may be stored under any names, like the other objects. They are stored
+// by value, the same as they are passed in
+func (w *World) AddLight(name string, light Light) {
+	w.Add(name, light)
 }
 
-func (w World) SetLight(pl PointLight) {
-	w["light"] = &pl
+// All the lights in the world sorted by their names, so they are always
+// summed up in the same order. The slice is shared, it must not be modified
+func (w *World) Lights() []Light {
+	return w.lights
 }
 
-func (w World) Sphere(objectName string) *Sphere {
-	obj, ok := w[objectName]
+func (w *World) Sphere(objectName string) *Sphere {
+	obj, ok := w.objects[objectName]
 	if !ok {
 		panic(fmt.Sprintf("No object with name %q in the world!\n", objectName))
 	}
@@ -64,18 +139,55 @@ func (w World) Sphere(objectName string) *Sphere {
 	return s
 }
 
-func (w World) IntersectWith(r *Ray) []Intersection {
+// Builds bounding volume hierarchy of all the shapes in the world (including the
+// ones nested in groups) to speed up intersections. Adding, replacing or removing
+// shapes, changing their transforms or adding children to the groups drops the
+// hierarchy, and every shape is tested until it's rebuilt
+func (w *World) BuildBVH() BVHStats {
+	names := []string{}
+	for name, obj := range w.objects {
+		if _, ok := obj.(Shape); ok {
+			names = append(names, name)
+		}
+	}
+	// map iteration order is random, but the hierarchy better be the same every time
+	sort.Strings(names)
+
+	stats := BVHStats{}
+	shapes := []Shape{}
+	for _, name := range names {
+		s := w.objects[name].(Shape)
+		stats = stats.add(buildNestedBVHs(s))
+		shapes = append(shapes, s)
+	}
+
+	b := buildBVH(shapes)
+	stats = stats.add(b.stats)
+	w.bvh = &worldBVH{bvh: b, stats: stats}
+	return stats
+}
+
+// Stats of the hierarchy built by BuildBVH. Returns false if there is no
+// hierarchy or it was dropped
+func (w *World) BVHStats() (BVHStats, bool) {
+	if w.bvh == nil {
+		return BVHStats{}, false
+	}
+	return w.bvh.stats, true
+}
+
+func (w *World) IntersectWith(r *Ray) []Intersection {
+	if w.bvh != nil {
+		allIntersections := w.bvh.bvh.intersect(r)
+		sort.Slice(allIntersections, func(i, j int) bool { return allIntersections[i].time < allIntersections[j].time })
+		return allIntersections
+	}
+
 	allIntersections := []Intersection{}
 
-	for _, obj := range w {
-		switch obj := obj.(type) {
-		case *Sphere:
-			xs := obj.IntersectWith(r)
-			allIntersections = append(allIntersections, xs...)
-		case *PointLight:
-			continue
-		default:
-			fmt.Printf("Intersection with type %T is not supported\n", obj)
+	for _, obj := range w.objects {
+		if s, ok := obj.(Shape); ok {
+			allIntersections = append(allIntersections, IntersectWith(s, r)...)
 		}
 	}
 
@@ -83,14 +195,15 @@ func (w World) IntersectWith(r *Ray) []Intersection {
 	return allIntersections
 }
 
-// Returns BLACK if ray doesn't intersect with any objects in the world
-func (w World) ColorAtIntersection(ray Ray) Color {
+// Returns BLACK if ray doesn't intersect with any objects in the world.
+// remaining limits the number of reflections followed, see MAX_REFLECTION_DEPTH
+func (w *World) ColorAtIntersection(ray Ray, remaining int) Color {
 	intersections := w.IntersectWith(&ray)
 	hit, ok := Hit(intersections)
 	if !ok {
 		return BLACK
 	}
 
-	comps := PrepareIntersectionComputations(hit, ray)
-	return ShadeHit(w, &comps)
+	comps := PrepareIntersectionComputations(hit, ray, intersections)
+	return ShadeHit(w, &comps, remaining)
 }
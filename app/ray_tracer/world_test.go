@@ -9,14 +9,13 @@ import (
 func TestCreatingEmptyWorld(t *testing.T) {
 	w := NewWorld()
 
-	require.Len(t, w, 0)
+	require.Equal(t, 0, w.Len())
 }
 
 func TestDefaultWorldContainsPointLightAndTwoSpheres(t *testing.T) {
 	w := NewDefaultWorld()
 
-	obj1, ok := w["s1"]
-	require.True(t, ok)
+	obj1 := w.Object("s1")
 	s1, ok := obj1.(*Sphere)
 	require.True(t, ok)
 	require.True(t, s1.material.color.Equal(NewColor(0.8, 1, 0.6)))
@@ -25,15 +24,13 @@ func TestDefaultWorldContainsPointLightAndTwoSpheres(t *testing.T) {
 	require.EqualValues(t, s1.material.specular, 0.2)
 	require.EqualValues(t, s1.material.shininess, 200)
 
-	obj2, ok := w["s2"]
-	require.True(t, ok)
+	obj2 := w.Object("s2")
 	s2, ok := obj2.(*Sphere)
 	require.True(t, ok)
 	require.True(t, s2.material.color.Equal(WHITE))
 
-	obj3, ok := w["light"]
-	require.True(t, ok)
-	light, ok := obj3.(*PointLight)
+	obj3 := w.Object("light")
+	light, ok := obj3.(PointLight)
 	require.True(t, ok)
 	require.True(t, light.intensity.Equal(WHITE))
 }
@@ -42,19 +39,41 @@ func TestObjectInWorldCanBeChangedThroughtTheReference(t *testing.T) {
 	w := NewDefaultWorld()
 	newId := "new_test_id"
 
-	obj1 := w["s1"]
+	obj1 := w.Object("s1")
 	s1 := obj1.(*Sphere)
 	s1.id = newId
 
-	sameObj := w["s1"]
+	sameObj := w.Object("s1")
 	sameS1 := sameObj.(*Sphere)
 
 	require.Equal(t, newId, sameS1.id)
 }
 
+func TestObjectInWorldMayBeReplacedAndRemoved(t *testing.T) {
+	w := NewDefaultWorld()
+	s := NewDefaultSphere()
+
+	w.Add("s1", &s)
+	require.Equal(t, &s, w.Object("s1"))
+	require.Equal(t, 3, w.Len())
+
+	w.Remove("s1")
+	require.Nil(t, w.Object("s1"))
+	require.Equal(t, 2, w.Len())
+}
+
+func TestOnlyShapesAndLightsMayBeAddedToWorld(t *testing.T) {
+	w := NewWorld()
+	s := NewDefaultSphere()
+
+	require.Panics(t, func() { w.Add("sphere", s) })
+	require.Panics(t, func() { w.Add("color", RED) })
+	require.Equal(t, 0, w.Len())
+}
+
 func TestIntersectionsWithWorldReturnedInAscendingOrder(t *testing.T) {
 	w := NewDefaultWorld()
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
 
 	xs := w.IntersectWith(&r)
 
@@ -67,20 +86,20 @@ func TestIntersectionsWithWorldReturnedInAscendingOrder(t *testing.T) {
 
 func TestTheColorWhenRayMissesIsBlack(t *testing.T) {
 	w := NewDefaultWorld()
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 1, 0))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 1, 0))
 
 	expect := BLACK
-	res := w.ColorAtIntersection(r)
+	res := w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH)
 
 	require.True(t, expect.Equal(res))
 }
 
 func TestTheColorWhenRayHitsTheOuterSphere(t *testing.T) {
 	w := NewDefaultWorld()
-	r := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
 
 	expect := NewColor(0.38066, 0.47583, 0.2855)
-	res := w.ColorAtIntersection(r)
+	res := w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH)
 
 	require.True(t, expect.Equal(res))
 }
@@ -91,10 +110,110 @@ func TestTheColorWhenRayHitsInnerSphere(t *testing.T) {
 	outer.material.ambient = 1
 	inner := w.Sphere("s2")
 	inner.material.ambient = 1
-	r := NewRay(NewPoint(0, 0, 0.75), NewVector(0, 0, -1))
+	r := NewRay(NewPoint3(0, 0, 0.75), NewVec3(0, 0, -1))
 
 	expect := inner.material.color
-	res := w.ColorAtIntersection(r)
+	res := w.ColorAtIntersection(r, MAX_REFLECTION_DEPTH)
 
 	require.True(t, expect.Equal(res))
 }
+
+func TestWorldIntersectsAnyShape(t *testing.T) {
+	w := NewWorld()
+	s := newTestShape()
+	s.SetTransform(NewTranslationMatrix(0, 0, 1))
+	w.Add("shape", s)
+	r := NewRay(NewPoint3(0, 0, -5), NewVec3(0, 0, 1))
+
+	w.IntersectWith(&r)
+
+	require.True(t, s.savedRay.origin.Equal(NewPoint3(0, 0, -6)))
+}
+
+func TestEveryKindOfShapeCastsShadowInWorld(t *testing.T) {
+	testCases := []struct {
+		name          string
+		shape         func() Shape
+		light         Point3
+		shadowed, lit Point3
+	}{
+		{"plane", func() Shape {
+			floor := NewDefaultPlane()
+			floor.SetTransform(NewTranslationMatrix(0, 1, 0))
+			return &floor
+		}, NewPoint3(0, 10, 0), NewPoint3(0, 0, 0), NewPoint3(0, 2, 0)},
+		{"cube", func() Shape {
+			c := NewDefaultCube()
+			c.SetTransform(NewTranslationMatrix(0, 5, 0))
+			return &c
+		}, NewPoint3(0, 10, 0), NewPoint3(0, 0, 0), NewPoint3(3, 0, 0)},
+		{"closed cylinder", func() Shape {
+			cyl := NewDefaultCylinder()
+			cyl.Truncate(4, 5, true)
+			return &cyl
+		}, NewPoint3(0, 10, 0), NewPoint3(0, 0, 0), NewPoint3(0, 6, 0)},
+		{"triangle", func() Shape {
+			tri := newTestTriangle()
+			return &tri
+		}, NewPoint3(0, 0.5, -10), NewPoint3(0, 0.5, 10), NewPoint3(3, 0.5, 10)},
+		// only the corners are left of the cube, the light shines between them
+		{"csg", func() Shape {
+			c := NewDefaultCube()
+			c.SetTransform(NewTranslationMatrix(0, 5, 0))
+			hollow := NewDefaultSphere()
+			hollow.SetTransform(NewScalingMatrix(1.2, 1.2, 1.2).Translate(0, 5, 0))
+			return NewCSG("corners", CSG_DIFFERENCE, &c, &hollow)
+		}, NewPoint3(0, 10, 0), NewPoint3(1.9, 0, 1.9), NewPoint3(0, 0, 0)},
+	}
+
+	for _, tc := range testCases {
+		w := NewWorld()
+		w.SetLight(NewPointLight(tc.light, WHITE))
+		w.Add(tc.name, tc.shape())
+
+		require.True(t, IsShadowed(w, w.Light(), tc.shadowed), tc.name)
+		require.False(t, IsShadowed(w, w.Light(), tc.lit), tc.name)
+	}
+}
+
+func TestWorldMayHaveManyLights(t *testing.T) {
+	w := NewDefaultWorld()
+	fill := NewPointLight(NewPoint3(10, 10, -10), NewColor(0.5, 0.5, 0.5))
+	w.AddLight("fill", fill)
+	rim := NewPointLight(NewPoint3(0, 10, 10), NewColor(0.2, 0.2, 0.2))
+	w.AddLight("rim", rim)
+
+	lights := w.Lights()
+
+	require.Equal(t, []Light{fill, w.Light(), rim}, lights)
+}
+
+func TestWorldLightMayBeOfAnyKind(t *testing.T) {
+	w := NewDefaultWorld()
+	area := NewAreaLight(NewPoint3(-1, 10, -1), NewVec3(2, 0, 0), 2, NewVec3(0, 0, 2), 2, WHITE)
+	w.AddLight("light", area)
+
+	require.Equal(t, area, w.Light())
+	require.Equal(t, []Light{area}, w.Lights())
+}
+
+func TestLightsStaySortedWhenReplacedAndRemoved(t *testing.T) {
+	w := NewDefaultWorld()
+	fill := NewPointLight(NewPoint3(10, 10, -10), NewColor(0.5, 0.5, 0.5))
+	w.AddLight("fill", fill)
+	rim := NewPointLight(NewPoint3(0, 10, 10), NewColor(0.2, 0.2, 0.2))
+	w.AddLight("rim", rim)
+	key := NewPointLight(NewPoint3(0, 10, -10), WHITE)
+
+	w.AddLight("light", key)
+	require.Equal(t, []Light{fill, key, rim}, w.Lights())
+
+	s := NewDefaultSphere()
+	w.Add("fill", &s)
+	w.Remove("rim")
+	require.Equal(t, []Light{key}, w.Lights())
+}
+
+func TestEmptyWorldHasNoLights(t *testing.T) {
+	require.Empty(t, NewWorld().Lights())
+}